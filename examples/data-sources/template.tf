@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+    local = {
+      source  = "hashicorp/local"
+      version = ">= 2.2.0"
+    }
+  }
+}
+
+provider "local" {}
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# All references are resolved with a single API call - $${...} encloses
+# references with whitespace, ex. record titles with spaces
+data "secretsmanager_template" "app_config" {
+  template = <<EOT
+database:
+  user: keeper://<record UID>/field/login
+  password: "keeper://<record UID>/field/password"
+api_token: keeper://api-token/custom_field/Token
+admin_password: $${keeper://Admin Account/field/password}
+EOT
+}
+
+resource "local_sensitive_file" "app_config" {
+  filename        = "${path.module}/app.yaml"
+  file_permission = "0600"
+  content         = data.secretsmanager_template.app_config.rendered
+}
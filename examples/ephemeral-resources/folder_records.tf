@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+  }
+}
+
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# Ephemeral resources do not store secret values in the Terraform state file.
+# Reads every record of the folder (and its subfolders).
+
+ephemeral "secretsmanager_folder_records" "app" {
+  folder_uid         = "<folder UID>"
+  include_subfolders = true
+}
+
+output "record_titles" {
+  value     = [for r in ephemeral.secretsmanager_folder_records.app.records : r.title]
+  ephemeral = true
+}
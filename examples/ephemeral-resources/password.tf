@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+  }
+}
+
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# Passwords generated by ephemeral resources are never stored in the state file.
+# Pass them to write-only attributes of other resources.
+
+ephemeral "secretsmanager_password" "db" {
+  length            = 24
+  caps              = 2
+  digits            = 2
+  special           = 2
+  exclude_ambiguous = true
+}
+
+# Pronounceable passphrase for systems that reject symbols
+ephemeral "secretsmanager_password" "wifi" {
+  passphrase = {
+    words      = 6
+    separator  = " "
+    capitalize = true
+  }
+}
+
+output "db_password" {
+  value     = ephemeral.secretsmanager_password.db.value
+  ephemeral = true
+}
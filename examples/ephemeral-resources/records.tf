@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+  }
+}
+
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# Ephemeral resources do not store secret values in the Terraform state file.
+# The same filters as the secretsmanager_records data source.
+
+ephemeral "secretsmanager_records" "batch" {
+  uids = [
+    "RECORD_UID_1",
+    "RECORD_UID_2"
+  ]
+
+  title_patterns = [
+    "^Production.*"
+  ]
+}
+
+output "record_titles" {
+  value     = [for r in ephemeral.secretsmanager_records.batch.records : r.title]
+  ephemeral = true
+}
+
+locals {
+  record_1 = jsondecode(ephemeral.secretsmanager_records.batch.records_by_uid["RECORD_UID_1"])
+}
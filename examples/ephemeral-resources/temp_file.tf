@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+  }
+}
+
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# The attachment is written to a temporary file readable by the owner only
+# and deleted when Terraform closes the ephemeral resource.
+
+ephemeral "secretsmanager_temp_file" "keystore" {
+  path      = "<record UID>"
+  file_name = "app.jks"
+}
+
+output "keystore_path" {
+  value     = ephemeral.secretsmanager_temp_file.keystore.file_path
+  ephemeral = true
+}
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    secretsmanager = {
+      source  = "keeper-security/secretsmanager"
+      version = ">= 1.3.0"
+    }
+  }
+}
+
+provider "secretsmanager" {
+  credential = "<CREDENTIAL>"
+  # credential = file("~/.keeper/credential")
+}
+
+# Ephemeral resources do not store secret values in the Terraform state file.
+# All references are resolved with a single API call.
+
+ephemeral "secretsmanager_template" "env" {
+  template = <<EOT
+DB_USER=keeper://<record UID>/field/login
+DB_PASSWORD=keeper://<record UID>/field/password
+EOT
+}
+
+output "env_file" {
+  value     = ephemeral.secretsmanager_template.env.rendered
+  ephemeral = true
+}
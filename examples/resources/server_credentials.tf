@@ -48,6 +48,10 @@ resource "secretsmanager_server_credentials" "my_server_credentials" {
       lowercase = 5
       digits    = 5
       special   = 5
+      # symbols the server accepts, no quotes or backslashes
+      special_characters     = "!#%+-.:=@_"
+      exclude_ambiguous      = true
+      must_start_with_letter = true
     }
     #value = "to_be_generated"
   }
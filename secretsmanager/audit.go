@@ -0,0 +1,357 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+const auditLogPathDescription = "Path to a local file the provider appends one JSON line to for every record and folder " +
+	"it creates, updates or deletes - UIDs, record types and changed field labels, never values. " +
+	"Can also be sourced from the `KEEPER_AUDIT_LOG_PATH` environment variable."
+
+// Audit log operations.
+const (
+	auditRecordCreate = "record_create"
+	auditRecordUpdate = "record_update"
+	auditRecordDelete = "record_delete"
+	auditFileUpload   = "file_upload"
+	auditFolderCreate = "folder_create"
+	auditFolderUpdate = "folder_update"
+	auditFolderDelete = "folder_delete"
+)
+
+// auditEntry is one line of the audit log. It never holds field values,
+// titles or folder names.
+type auditEntry struct {
+	Time          string   `json:"time"`
+	Operation     string   `json:"operation"`
+	RecordUid     string   `json:"record_uid,omitempty"`
+	RecordType    string   `json:"record_type,omitempty"`
+	FolderUid     string   `json:"folder_uid,omitempty"`
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	Resource      string   `json:"resource,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// auditSnapshot is a record as last fetched - updates are compared against it
+// to find the changed fields.
+type auditSnapshot struct {
+	recordType string
+	folderUid  string
+	data       map[string]interface{}
+}
+
+// auditLog appends the entries to the audit_log_path file.
+type auditLog struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]auditSnapshot
+}
+
+// auditedClient carries the audit log of the provider configuration with its
+// client - the write helpers look it up with auditTrailOf.
+type auditedClient struct {
+	ksmClient
+	log *auditLog
+}
+
+func (c *auditedClient) unwrap() ksmClient { return c.ksmClient }
+
+// GetSecrets remembers the fetched records for the changed fields of updates.
+func (c *auditedClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	records, err := c.ksmClient.GetSecrets(uids)
+	if err == nil {
+		for _, r := range records {
+			c.log.remember(r.Uid, r.Type(), recordFolderUid(r), core.JsonToDict(r.RawJson))
+		}
+	}
+	return records, err
+}
+
+// auditScope tags the changes a managed resource makes with its resource type.
+type auditScope struct {
+	ksmClient
+	resource string
+}
+
+func (c *auditScope) unwrap() ksmClient { return c.ksmClient }
+
+// configureAuditLog wraps the client so changes are written to the audit log.
+// The client is returned unchanged when no path is set.
+func configureAuditLog(client ksmClient, path string) (ksmClient, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return client, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit_log_path: %w", err)
+	}
+	_ = f.Close()
+	return &auditedClient{ksmClient: client, log: &auditLog{path: path, records: map[string]auditSnapshot{}}}, nil
+}
+
+// auditResource scopes the client of a resource create, update or delete so
+// its changes are logged with the resource type. Terraform doesn't pass the
+// resource address to providers.
+func auditResource(resource string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if provider, ok := m.(providerMeta); ok && auditTrailOf(provider.client) != nil {
+			provider.client = &auditScope{ksmClient: provider.client, resource: resource}
+			m = provider
+		}
+		return fn(ctx, d, m)
+	}
+}
+
+// auditTrail is the audit log of a client with the resource making the change.
+type auditTrail struct {
+	log      *auditLog
+	resource string
+}
+
+// auditTrailOf returns the audit trail of the client, nil if it has none.
+func auditTrailOf(client ksmClient) *auditTrail {
+	resource := ""
+	for client != nil {
+		switch c := client.(type) {
+		case *auditScope:
+			if resource == "" {
+				resource = c.resource
+			}
+		case *auditedClient:
+			return &auditTrail{log: c.log, resource: resource}
+		}
+		w, ok := client.(wrappedClient)
+		if !ok {
+			return nil
+		}
+		client = w.unwrap()
+	}
+	return nil
+}
+
+// remembers reports whether the record was fetched - always true without a trail.
+func (t *auditTrail) remembers(uid string) bool {
+	if t == nil {
+		return true
+	}
+	_, found := t.log.snapshot(uid)
+	return found
+}
+
+// recordCreated logs a new record with all its fields as changed.
+func (t *auditTrail) recordCreated(uid, folderUid string, record *core.RecordCreate, err error) {
+	if t == nil {
+		return
+	}
+	data := core.JsonToDict(record.ToJson())
+	entry := auditEntry{Operation: auditRecordCreate, RecordUid: uid, RecordType: record.RecordType,
+		FolderUid: folderUid, ChangedFields: changedFieldLabels(nil, &core.Record{RecordDict: data})}
+	if err == nil {
+		t.log.remember(uid, record.RecordType, folderUid, data)
+	}
+	t.write(entry, err)
+}
+
+// recordSaved logs an updated record with the fields changed since it was fetched.
+func (t *auditTrail) recordSaved(record *core.Record, err error) {
+	if t == nil {
+		return
+	}
+	data := core.JsonToDict(record.RawJson)
+	var older *core.Record
+	if before, found := t.log.snapshot(record.Uid); found {
+		older = &core.Record{RecordDict: before.data}
+	}
+	entry := auditEntry{Operation: auditRecordUpdate, RecordUid: record.Uid, RecordType: record.Type(),
+		FolderUid: recordFolderUid(record), ChangedFields: changedFieldLabels(older, &core.Record{RecordDict: data})}
+	if err == nil {
+		t.log.remember(record.Uid, record.Type(), entry.FolderUid, data)
+	}
+	t.write(entry, err)
+}
+
+// recordDeleted logs a deleted record with the type and folder it was last seen with.
+func (t *auditTrail) recordDeleted(uid string, err error) {
+	if t == nil {
+		return
+	}
+	before, _ := t.log.snapshot(uid)
+	entry := auditEntry{Operation: auditRecordDelete, RecordUid: uid, RecordType: before.recordType, FolderUid: before.folderUid}
+	if err == nil {
+		t.log.forget(uid)
+	}
+	t.write(entry, err)
+}
+
+// fileUploaded logs a file added to the record.
+func (t *auditTrail) fileUploaded(record *core.Record, err error) {
+	if t == nil {
+		return
+	}
+	t.write(auditEntry{Operation: auditFileUpload, RecordUid: record.Uid, RecordType: record.Type(),
+		FolderUid: recordFolderUid(record), ChangedFields: []string{"fileRef"}}, err)
+}
+
+// folderChanged logs a created, renamed or deleted folder.
+func (t *auditTrail) folderChanged(operation, folderUid string, err error) {
+	if t == nil {
+		return
+	}
+	entry := auditEntry{Operation: operation, FolderUid: folderUid}
+	if operation != auditFolderDelete {
+		entry.ChangedFields = []string{"name"}
+	}
+	t.write(entry, err)
+}
+
+// write appends the entry to the log. The change is already made in Keeper
+// so a failed write is only logged.
+func (t *auditTrail) write(entry auditEntry, err error) {
+	entry.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	entry.Resource = t.resource
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	line, e := json.Marshal(entry)
+	if e == nil {
+		e = t.log.append(append(line, '\n'))
+	}
+	if e != nil {
+		log.Printf("[WARN] error writing audit log %s: %v", t.log.path, e)
+	}
+}
+
+func (l *auditLog) append(line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (l *auditLog) remember(uid, recordType, folderUid string, data map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records[uid] = auditSnapshot{recordType: recordType, folderUid: folderUid, data: data}
+}
+
+func (l *auditLog) forget(uid string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.records, uid)
+}
+
+func (l *auditLog) snapshot(uid string) (auditSnapshot, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.records[uid]
+	return s, ok
+}
+
+// recordFolderUid returns the folder of the record - its subfolder if it has one.
+func recordFolderUid(record *core.Record) string {
+	if uid := record.InnerFolderUid(); uid != "" {
+		return uid
+	}
+	return record.FolderUid()
+}
+
+// recordFieldValues maps field label (or type when unlabeled) to its value
+// across both the standard and custom field sections.
+func recordFieldValues(record *core.Record) map[string]interface{} {
+	values := map[string]interface{}{}
+	if record == nil {
+		return values
+	}
+	for _, section := range []string{"fields", "custom"} {
+		flds, _ := record.RecordDict[section].([]interface{})
+		for _, fld := range flds {
+			if m, ok := fld.(map[string]interface{}); ok {
+				values[recordFieldName(m)] = m["value"]
+			}
+		}
+	}
+	return values
+}
+
+func recordFieldName(field map[string]interface{}) string {
+	if label, _ := field["label"].(string); label != "" {
+		return label
+	}
+	fieldType, _ := field["type"].(string)
+	return fieldType
+}
+
+// changedFieldLabels returns the labels of the fields that differ between two
+// revisions of a record, in the field order of the newer revision followed by
+// fields removed from the older one. Title and notes changes are reported as
+// "title" and "notes".
+func changedFieldLabels(older, newer *core.Record) []string {
+	changed := []string{}
+	if older != nil && newer != nil {
+		if older.Title() != newer.Title() {
+			changed = append(changed, "title")
+		}
+		if older.Notes() != newer.Notes() {
+			changed = append(changed, "notes")
+		}
+	}
+
+	oldValues := recordFieldValues(older)
+	newValues := recordFieldValues(newer)
+	seen := map[string]bool{}
+	for _, section := range []string{"fields", "custom"} {
+		flds, _ := newer.RecordDict[section].([]interface{})
+		for _, fld := range flds {
+			m, ok := fld.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := recordFieldName(m)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if oldValue, found := oldValues[name]; !found || !reflect.DeepEqual(oldValue, newValues[name]) {
+				changed = append(changed, name)
+			}
+		}
+	}
+	for _, section := range []string{"fields", "custom"} {
+		if older == nil {
+			break
+		}
+		flds, _ := older.RecordDict[section].([]interface{})
+		for _, fld := range flds {
+			if m, ok := fld.(map[string]interface{}); ok {
+				if name := recordFieldName(m); !seen[name] {
+					seen[name] = true
+					changed = append(changed, name)
+				}
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,145 @@
+package secretsmanager
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer f.Close()
+	entries := []auditEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestConfigureAuditLog(t *testing.T) {
+	vault := newFakeVault()
+	if client, err := configureAuditLog(vault, " "); err != nil || client != vault || auditTrailOf(client) != nil {
+		t.Errorf("expected the client unchanged without a path: %v", err)
+	}
+	if _, err := configureAuditLog(vault, filepath.Join(t.TempDir(), "missing", "audit.jsonl")); err == nil {
+		t.Error("expected an error for a path in a missing directory")
+	}
+}
+
+func TestAuditLogRecordLifecycle(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client, err := configureAuditLog(vault, path)
+	if err != nil {
+		t.Fatalf("configureAuditLog: %v", err)
+	}
+	// the audit log is found through the cache wrapper too
+	cached, _ := newTestCachingClient(t, client, false)
+	meta := providerMeta{client: cached}
+	r := Provider().ResourcesMap["secretsmanager_login"]
+
+	config := map[string]interface{}{"folder_uid": folderUid, "title": "my web", "notes": "top secret note"}
+	diff, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("plan create: %v", err)
+	}
+	state, diags := r.Apply(ctx, nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	config["notes"] = "another secret note"
+	diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("plan update: %v", err)
+	}
+	if state, diags = r.Apply(ctx, state, diff, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if diags := r.DeleteContext(ctx, r.Data(state), meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if _, err := createFolder(ctx, folderUid, "team", meta.client); err != nil {
+		t.Fatalf("createFolder: %v", err)
+	}
+
+	entries := readAuditLog(t, path)
+	operations := []string{}
+	for _, e := range entries {
+		operations = append(operations, e.Operation)
+	}
+	want := []string{auditRecordCreate, auditRecordUpdate, auditRecordDelete, auditFolderCreate}
+	if !slices.Equal(operations, want) {
+		t.Fatalf("operations = %v, want %v", operations, want)
+	}
+	for _, e := range entries[:3] {
+		if e.RecordUid != state.ID || e.RecordType != "login" || e.FolderUid != folderUid ||
+			e.Resource != "secretsmanager_login" || e.Time == "" || e.Error != "" {
+			t.Errorf("unexpected record entry: %+v", e)
+		}
+	}
+	if !slices.Equal(entries[1].ChangedFields, []string{"notes"}) {
+		t.Errorf("update changed fields = %v, want [notes]", entries[1].ChangedFields)
+	}
+	if entries[3].Resource != "" || entries[3].FolderUid == "" {
+		t.Errorf("unexpected folder entry: %+v", entries[3])
+	}
+
+	content, _ := os.ReadFile(path)
+	if strings.Contains(string(content), "secret note") || strings.Contains(string(content), "my web") {
+		t.Errorf("audit log contains record values: %s", content)
+	}
+}
+
+func auditTestRecord(title, password, owner string) *core.Record {
+	return &core.Record{
+		RecordDict: map[string]interface{}{
+			"type":  "login",
+			"title": title,
+			"fields": []interface{}{
+				map[string]interface{}{"type": "login", "value": []interface{}{"admin"}},
+				map[string]interface{}{"type": "password", "value": []interface{}{password}},
+			},
+			"custom": []interface{}{
+				map[string]interface{}{"type": "text", "label": "Owner", "value": []interface{}{owner}},
+			},
+		},
+	}
+}
+
+func TestChangedFieldLabels(t *testing.T) {
+	older := auditTestRecord("db-old", "initial", "dev")
+	newer := auditTestRecord("db", "rotated-1", "dev")
+	if got := changedFieldLabels(older, newer); !reflect.DeepEqual(got, []string{"title", "password"}) {
+		t.Errorf("changedFieldLabels = %v", got)
+	}
+
+	newer.RecordDict["custom"] = []interface{}{}
+	if got := changedFieldLabels(older, newer); !reflect.DeepEqual(got, []string{"title", "password", "Owner"}) {
+		t.Errorf("changedFieldLabels with removed field = %v", got)
+	}
+
+	// new record - every field is new
+	if got := changedFieldLabels(nil, older); !reflect.DeepEqual(got, []string{"login", "password", "Owner"}) {
+		t.Errorf("changedFieldLabels of new record = %v", got)
+	}
+}
@@ -0,0 +1,430 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// Provider offline cache settings - both providers read them with the same
+// environment variable fallbacks.
+const (
+	cacheFileDescription = "Path to a local file that keeps the records fetched from Keeper, encrypted with the application key " +
+		"from `credential`. Can also be sourced from the `KEEPER_CACHE_FILE` environment variable."
+	fallbackToCacheDescription = "When Keeper can not be reached, data sources and ephemeral resources serve the last fetched " +
+		"records from `cache_file` with a warning. Managed resources always require Keeper. File content is not cached. " +
+		"Can also be sourced from the `KEEPER_FALLBACK_TO_CACHE` environment variable."
+)
+
+// cacheSettings are the provider offline cache settings.
+type cacheSettings struct {
+	CacheFile       string
+	FallbackToCache bool
+}
+
+// withEnvDefaults fills unset values from the environment.
+func (c cacheSettings) withEnvDefaults() cacheSettings {
+	if c.CacheFile == "" {
+		c.CacheFile = envDefault("KEEPER_CACHE_FILE")
+	}
+	if !c.FallbackToCache {
+		c.FallbackToCache, _ = core.StrToBool(envDefault("KEEPER_FALLBACK_TO_CACHE"))
+	}
+	return c
+}
+
+// configureCache wraps the client so fetched records are written to the cache
+// file. The client is returned unchanged when no cache file is set.
+func configureCache(client ksmClient, config core.IKeyValueStorage, settings cacheSettings) (ksmClient, error) {
+	cacheFile := strings.TrimSpace(settings.CacheFile)
+	if cacheFile == "" {
+		if settings.FallbackToCache {
+			return nil, errors.New("fallback_to_cache requires cache_file")
+		}
+		return client, nil
+	}
+
+	key := core.Base64ToBytes(config.Get(core.KEY_APP_KEY))
+	if len(key) != 32 {
+		return nil, errors.New("cache_file requires a credential with a valid application key")
+	}
+	cache, err := openRecordCache(cacheFile, key)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingClient{ksmClient: client, cache: cache, fallback: settings.FallbackToCache}, nil
+}
+
+// cachedRecord is a record as stored in the cache file. RecordKey keeps the
+// record usable with the SDK - the whole file is encrypted with the app key.
+type cachedRecord struct {
+	Uid            string       `json:"uid"`
+	FolderUid      string       `json:"folderUid,omitempty"`
+	InnerFolderUid string       `json:"innerFolderUid,omitempty"`
+	Revision       int64        `json:"revision"`
+	IsEditable     bool         `json:"isEditable"`
+	RecordKey      []byte       `json:"recordKey"`
+	Data           string       `json:"data"`
+	Files          []cachedFile `json:"files,omitempty"`
+	FetchedAt      time.Time    `json:"fetchedAt"`
+}
+
+// cachedFile keeps file metadata only - file content is downloaded on demand.
+type cachedFile struct {
+	Uid          string `json:"uid"`
+	Type         string `json:"type,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Name         string `json:"name"`
+	LastModified int    `json:"lastModified"`
+	Size         int    `json:"size"`
+}
+
+func newCachedRecord(record *core.Record, fetchedAt time.Time) *cachedRecord {
+	cached := &cachedRecord{
+		Uid:            record.Uid,
+		FolderUid:      record.FolderUid(),
+		InnerFolderUid: record.InnerFolderUid(),
+		Revision:       record.Revision,
+		IsEditable:     record.IsEditable,
+		RecordKey:      record.RecordKeyBytes,
+		Data:           record.RawJson,
+		FetchedAt:      fetchedAt,
+	}
+	for _, f := range record.Files {
+		cached.Files = append(cached.Files, cachedFile{
+			Uid:          f.Uid,
+			Type:         f.Type,
+			Title:        f.Title,
+			Name:         f.Name,
+			LastModified: f.LastModified,
+			Size:         f.Size,
+		})
+	}
+	return cached
+}
+
+// toRecord restores the SDK record the same way it is decrypted from an API response.
+func (r *cachedRecord) toRecord() (*core.Record, error) {
+	data, err := core.EncryptAesGcm([]byte(r.Data), r.RecordKey)
+	if err != nil {
+		return nil, fmt.Errorf("error restoring cached record UID %s: %w", r.Uid, err)
+	}
+	recordDict := map[string]interface{}{
+		"recordUid":  r.Uid,
+		"data":       core.BytesToBase64(data),
+		"revision":   float64(r.Revision),
+		"isEditable": r.IsEditable,
+	}
+	if r.InnerFolderUid != "" {
+		recordDict["innerFolderUid"] = r.InnerFolderUid
+	}
+	record := core.NewRecordFromJson(recordDict, r.RecordKey, r.FolderUid)
+	for _, f := range r.Files {
+		record.Files = append(record.Files, &core.KeeperFile{
+			Uid:            f.Uid,
+			Type:           f.Type,
+			Title:          f.Title,
+			Name:           f.Name,
+			LastModified:   f.LastModified,
+			Size:           f.Size,
+			F:              map[string]interface{}{},
+			RecordKeyBytes: r.RecordKey,
+		})
+	}
+	return record, nil
+}
+
+// recordCache is the encrypted cache file. Both providers use the same instance
+// for a path so their writes don't overwrite each other.
+type recordCache struct {
+	mu      sync.Mutex
+	path    string
+	key     []byte
+	records map[string]*cachedRecord
+}
+
+var (
+	recordCachesMu sync.Mutex
+	recordCaches   = map[string]*recordCache{}
+)
+
+// openRecordCache loads the cache file, creating it when missing so an unusable
+// path fails provider configuration instead of every later read.
+func openRecordCache(path string, key []byte) (*recordCache, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache_file '%s': %w", path, err)
+	}
+
+	recordCachesMu.Lock()
+	defer recordCachesMu.Unlock()
+	if cache, found := recordCaches[absPath]; found {
+		if string(cache.key) != string(key) {
+			return nil, fmt.Errorf("cache_file '%s' is already used with a different credential", path)
+		}
+		return cache, nil
+	}
+
+	cache := &recordCache{path: absPath, key: key, records: map[string]*cachedRecord{}}
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+	if err := cache.save(); err != nil {
+		return nil, err
+	}
+	recordCaches[absPath] = cache
+	return cache, nil
+}
+
+func (c *recordCache) load() error {
+	content, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading cache_file: %w", err)
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	data, err := core.Decrypt(content, c.key)
+	if err != nil {
+		return fmt.Errorf("error decrypting cache_file '%s' - it was written with a different credential: %w", c.path, err)
+	}
+	if err := json.Unmarshal(data, &c.records); err != nil {
+		return fmt.Errorf("error parsing cache_file '%s': %w", c.path, err)
+	}
+	return nil
+}
+
+// save writes the cache to a temporary file and renames it so readers never
+// see a partially written cache.
+func (c *recordCache) save() error {
+	data, err := json.Marshal(c.records)
+	if err != nil {
+		return err
+	}
+	content, err := core.EncryptAesGcm(data, c.key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("error creating cache_file folder: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing cache_file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing cache_file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache_file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("error writing cache_file: %w", err)
+	}
+	return nil
+}
+
+// store adds the fetched records to the cache and drops the requested ones that
+// were not returned - a full fetch (no UIDs) replaces the whole cache.
+func (c *recordCache) store(uids []string, records []*core.Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(uids) == 0 {
+		c.records = map[string]*cachedRecord{}
+	}
+	for _, uid := range uids {
+		delete(c.records, uid)
+	}
+	now := time.Now().UTC()
+	for _, record := range records {
+		if record != nil && record.Uid != "" && record.RawJson != "" {
+			c.records[record.Uid] = newCachedRecord(record, now)
+		}
+	}
+	return c.save()
+}
+
+// lookup returns the cached records for the UIDs (all records when empty) and
+// the time the oldest of them was fetched. Found is false if any UID is missing.
+func (c *recordCache) lookup(uids []string) (records []*core.Record, fetchedAt time.Time, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached := []*cachedRecord{}
+	if len(uids) == 0 {
+		for _, r := range c.records {
+			cached = append(cached, r)
+		}
+	} else {
+		for _, uid := range uids {
+			r, ok := c.records[uid]
+			if !ok {
+				return nil, time.Time{}, false
+			}
+			cached = append(cached, r)
+		}
+	}
+	if len(cached) == 0 {
+		return nil, time.Time{}, false
+	}
+
+	for _, r := range cached {
+		record, err := r.toRecord()
+		if err != nil {
+			return nil, time.Time{}, false
+		}
+		records = append(records, record)
+		if fetchedAt.IsZero() || r.FetchedAt.Before(fetchedAt) {
+			fetchedAt = r.FetchedAt
+		}
+	}
+	return records, fetchedAt, true
+}
+
+// cachingClient writes the records it fetches to the cache file. Reads only fall
+// back to the cache through readRecord, readSecrets and readNotation - managed
+// resources must never plan or save against cached records.
+type cachingClient struct {
+	ksmClient
+	cache    *recordCache
+	fallback bool
+}
+
+func (c *cachingClient) unwrap() ksmClient { return c.ksmClient }
+
+func (c *cachingClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	records, err := c.ksmClient.GetSecrets(uids)
+	if err == nil {
+		// the cache is best effort - the file was writable when the provider was configured
+		_ = c.cache.store(uids, records)
+	}
+	return records, err
+}
+
+// cacheFallback describes records served from the cache file because the API
+// request failed. A nil *cacheFallback means the records came from Keeper.
+type cacheFallback struct {
+	err       error
+	fetchedAt time.Time
+}
+
+func (f *cacheFallback) summary() string {
+	return "Serving cached Keeper records"
+}
+
+func (f *cacheFallback) detail() string {
+	return fmt.Sprintf("Keeper could not be reached - using values from cache_file fetched at %s. Error: %v",
+		f.fetchedAt.Format(time.RFC3339), f.err)
+}
+
+// diagnostics returns the SDKv2 warning for data sources.
+func (f *cacheFallback) diagnostics() diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return diag.Diagnostics{{Severity: diag.Warning, Summary: f.summary(), Detail: f.detail()}}
+}
+
+// addWarning adds the Framework warning for ephemeral resources.
+func (f *cacheFallback) addWarning(diags *fwdiag.Diagnostics) {
+	if f != nil {
+		diags.AddWarning(f.summary(), f.detail())
+	}
+}
+
+// fallbackClient is a single read through the caching client that serves the
+// cache when Keeper fails and remembers it did.
+type fallbackClient struct {
+	*cachingClient
+	used *cacheFallback
+}
+
+func (c *fallbackClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	records, err := c.cachingClient.GetSecrets(uids)
+	if err == nil || isThrottled(err) {
+		return records, err
+	}
+	cached, fetchedAt, found := c.cache.lookup(uids)
+	if !found {
+		return records, err
+	}
+	c.used = &cacheFallback{err: err, fetchedAt: fetchedAt}
+	return cached, nil
+}
+
+func (c *fallbackClient) GetNotation(notation string) ([]interface{}, error) {
+	values, err := c.cachingClient.GetNotation(notation)
+	if err == nil || isThrottled(err) {
+		return values, err
+	}
+	cached, fetchedAt, found := c.cache.lookup(nil)
+	if !found {
+		return values, err
+	}
+	// FindNotation resolves the notation against the given records only
+	cachedValues, ferr := (&core.SecretsManager{}).FindNotation(cached, notation)
+	if ferr != nil {
+		return values, err
+	}
+	c.used = &cacheFallback{err: err, fetchedAt: fetchedAt}
+	return cachedValues, nil
+}
+
+// readClient returns the client for a data source or ephemeral resource read -
+// it falls back to the cache when the provider has fallback_to_cache set.
+func readClient(client ksmClient) (ksmClient, *fallbackClient) {
+	switch c := client.(type) {
+	case *cachingClient:
+		if c.fallback {
+			fc := &fallbackClient{cachingClient: c}
+			return fc, fc
+		}
+	case *apiScope:
+		inner, fc := readClient(c.ksmClient)
+		return &apiScope{ksmClient: inner, apiLog: c.apiLog}, fc
+	}
+	return client, nil
+}
+
+func (c *fallbackClient) fallbackUsed() *cacheFallback {
+	if c == nil {
+		return nil
+	}
+	return c.used
+}
+
+// readRecord is getRecord for data sources and ephemeral resources.
+func readRecord(ctx context.Context, path string, title string, client ksmClient) (*core.Record, *cacheFallback, error) {
+	client, fc := readClient(client)
+	secret, err := getRecord(ctx, path, title, client)
+	return secret, fc.fallbackUsed(), err
+}
+
+// readSecrets is getSecrets for data sources and ephemeral resources.
+func readSecrets(ctx context.Context, client ksmClient, uids []string) ([]*core.Record, *cacheFallback, error) {
+	client, fc := readClient(client)
+	records, err := getSecrets(ctx, client, uids)
+	return records, fc.fallbackUsed(), err
+}
+
+// readNotation is getNotation for data sources and ephemeral resources - a
+// notation selecting nothing returns a notationMatchError.
+func readNotation(ctx context.Context, client ksmClient, notation string) ([]interface{}, *cacheFallback, error) {
+	client, fc := readClient(client)
+	values, err := getNotation(ctx, client, notation)
+	return values, fc.fallbackUsed(), notationError(notation, err)
+}
@@ -0,0 +1,199 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+var errFakeUnreachable = errors.New("dial tcp: lookup keepersecurity.com: no such host")
+
+func newTestCacheConfig(t *testing.T) core.IKeyValueStorage {
+	t.Helper()
+	key, _ := core.GetRandomBytes(32)
+	config := core.NewMemoryKeyValueStorage()
+	config.Set(core.KEY_APP_KEY, core.BytesToBase64(key))
+	return config
+}
+
+func newTestCachingClient(t *testing.T, client ksmClient, fallback bool) (*cachingClient, string) {
+	t.Helper()
+	cacheFile := filepath.Join(t.TempDir(), "cache", "records.bin")
+	t.Cleanup(func() {
+		recordCachesMu.Lock()
+		defer recordCachesMu.Unlock()
+		for path := range recordCaches {
+			delete(recordCaches, path)
+		}
+	})
+	cached, err := configureCache(client, newTestCacheConfig(t), cacheSettings{CacheFile: cacheFile, FallbackToCache: fallback})
+	if err != nil {
+		t.Fatalf("configureCache: %v", err)
+	}
+	return cached.(*cachingClient), cacheFile
+}
+
+func TestConfigureCache(t *testing.T) {
+	vault := newFakeVault()
+	config := newTestCacheConfig(t)
+
+	client, err := configureCache(vault, config, cacheSettings{})
+	if err != nil || client != vault {
+		t.Errorf("expected the client unchanged without cache_file, got %T, %v", client, err)
+	}
+	if _, err := configureCache(vault, config, cacheSettings{FallbackToCache: true}); err == nil {
+		t.Error("expected an error for fallback_to_cache without cache_file")
+	}
+
+	badConfig := core.NewMemoryKeyValueStorage()
+	badConfig.Set(core.KEY_APP_KEY, core.BytesToBase64([]byte("short")))
+	if _, err := configureCache(vault, badConfig, cacheSettings{CacheFile: filepath.Join(t.TempDir(), "c")}); err == nil {
+		t.Error("expected an error for an invalid application key")
+	}
+
+	t.Setenv("KEEPER_CACHE_FILE", "/tmp/ksm.cache")
+	t.Setenv("KEEPER_FALLBACK_TO_CACHE", "true")
+	settings := cacheSettings{}.withEnvDefaults()
+	if settings.CacheFile != "/tmp/ksm.cache" || !settings.FallbackToCache {
+		t.Errorf("unexpected env defaults: %+v", settings)
+	}
+}
+
+func TestCacheFallback(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}}},
+	})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+	client, cacheFile := newTestCachingClient(t, vault, true)
+
+	record, fallback, err := readRecord(ctx, uid, "", client)
+	if err != nil || fallback != nil {
+		t.Fatalf("live read: fallback=%v err=%v", fallback, err)
+	}
+
+	content, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatalf("reading cache_file: %v", err)
+	}
+	if strings.Contains(string(content), "s3cr3t") || strings.Contains(string(content), uid) {
+		t.Error("cache_file is not encrypted")
+	}
+
+	vault.err = errFakeUnreachable
+	cached, fallback, err := readRecord(ctx, uid, "", client)
+	if err != nil {
+		t.Fatalf("cached read: %v", err)
+	}
+	if fallback == nil || !errors.Is(fallback.err, errFakeUnreachable) {
+		t.Fatalf("expected a cache fallback, got %v", fallback)
+	}
+	if cached.GetFieldValueByType("password") != "s3cr3t" || cached.Revision != record.Revision ||
+		cached.FolderUid() != folderUid || cached.RawJson != record.RawJson {
+		t.Errorf("cached record differs from the fetched one: %s", cached.RawJson)
+	}
+	if len(cached.Files) != 1 || cached.Files[0].Name != "cert.pem" {
+		t.Errorf("unexpected cached files: %v", cached.Files)
+	}
+
+	if _, fallback, err = readRecord(ctx, "*", "web", client); err != nil || fallback == nil {
+		t.Errorf("cached read by title: fallback=%v err=%v", fallback, err)
+	}
+	values, fallback, err := readNotation(ctx, client, uid+"/field/password")
+	if err != nil || fallback == nil || len(values) != 1 || values[0] != "s3cr3t" {
+		t.Errorf("cached notation: values=%v fallback=%v err=%v", values, fallback, err)
+	}
+	if _, _, err = readRecord(ctx, core.GenerateUid(), "", client); !errors.Is(err, errFakeUnreachable) {
+		t.Errorf("expected the API error for a record not in cache, got %v", err)
+	}
+
+	// managed resources never read from the cache
+	if _, err = getRecord(ctx, uid, "", client); !errors.Is(err, errFakeUnreachable) {
+		t.Errorf("getRecord should not fall back to the cache, got %v", err)
+	}
+}
+
+func TestCacheWithoutFallback(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	client, cacheFile := newTestCachingClient(t, vault, false)
+
+	if _, _, err := readRecord(ctx, uid, "", client); err != nil {
+		t.Fatalf("live read: %v", err)
+	}
+	vault.err = errFakeUnreachable
+	if _, fallback, err := readRecord(ctx, uid, "", client); err == nil || fallback != nil {
+		t.Errorf("expected the API error without fallback_to_cache, got fallback=%v err=%v", fallback, err)
+	}
+
+	// the cache file is only readable with the same application key
+	recordCachesMu.Lock()
+	delete(recordCaches, cacheFile)
+	recordCachesMu.Unlock()
+	if _, err := configureCache(vault, newTestCacheConfig(t), cacheSettings{CacheFile: cacheFile}); err == nil {
+		t.Error("expected an error opening the cache with another credential")
+	}
+}
+
+func TestCacheStoreDropsMissingRecords(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	other := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "db"})
+	client, _ := newTestCachingClient(t, vault, true)
+
+	if _, _, err := readSecrets(ctx, client, []string{}); err != nil {
+		t.Fatalf("live read: %v", err)
+	}
+	if _, _, found := client.cache.lookup([]string{uid, other}); !found {
+		t.Fatal("expected both records cached")
+	}
+
+	vault.mu.Lock()
+	vault.records = vault.records[:1]
+	vault.mu.Unlock()
+	if _, _, err := readSecrets(ctx, client, []string{other}); err != nil {
+		t.Fatalf("live read: %v", err)
+	}
+	if _, _, found := client.cache.lookup([]string{other}); found {
+		t.Error("record no longer shared should be dropped from the cache")
+	}
+}
+
+func TestDataSourceLoginCacheFallback(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+	client, _ := newTestCachingClient(t, vault, true)
+	meta := providerMeta{client: client}
+
+	d := schema.TestResourceDataRaw(t, dataSourceLogin().Schema, map[string]interface{}{"path": uid})
+	if diags := dataSourceLoginRead(ctx, d, meta); len(diags) != 0 {
+		t.Fatalf("live read: %v", diags)
+	}
+
+	vault.err = errFakeUnreachable
+	d = schema.TestResourceDataRaw(t, dataSourceLogin().Schema, map[string]interface{}{"path": uid})
+	diags := dataSourceLoginRead(ctx, d, meta)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+	if got := d.Get("login").(string); got != "admin" {
+		t.Errorf("login = %q, want admin", got)
+	}
+}
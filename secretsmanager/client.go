@@ -0,0 +1,60 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// ksmClient is the subset of the Secrets Manager SDK client used by the provider.
+// *core.SecretsManager implements it - tests swap in an in-memory fake vault.
+// Terraform calls the provider from concurrent goroutines, so the wrappers of
+// the provider's client must be safe for concurrent use - see sharedClient.
+type ksmClient interface {
+	GetSecrets(uids []string) ([]*core.Record, error)
+	Save(record *core.Record) error
+	DeleteSecrets(recordUids []string) (map[string]string, error)
+	CreateSecretWithRecordDataUidAndOptions(recUid string, createOptions *core.CreateOptions, recordData *core.RecordCreate, folders []*core.KeeperFolder) (string, error)
+	GetFolders() ([]*core.KeeperFolder, error)
+	CreateFolder(createOptions core.CreateOptions, folderName string, folders []*core.KeeperFolder) (string, error)
+	UpdateFolder(folderUid, folderName string, folders []*core.KeeperFolder) error
+	DeleteFolder(folderUids []string, forceDeletion bool) (map[string]string, error)
+	GetNotation(notation string) ([]interface{}, error)
+	UploadFile(record *core.Record, file *core.KeeperFileUpload) (string, error)
+}
+
+var _ ksmClient = (*core.SecretsManager)(nil)
+
+// newKsmClient creates the client used by both providers from a validated KSM config.
+var newKsmClient = newSecretsManagerClient
+
+func newSecretsManagerClient(config core.IKeyValueStorage) ksmClient {
+	return core.NewSecretsManager(&core.ClientOptions{Config: config})
+}
+
+// throttleRetryDelay is the wait between retries of throttled requests.
+var throttleRetryDelay = 11 * time.Second
+
+// retryWait waits throttleRetryDelay before retrying a throttled request. It
+// stops early when the operation is canceled or times out.
+func retryWait(ctx context.Context) error {
+	timer := time.NewTimer(throttleRetryDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+}
+
+// contextError returns the error of a canceled or timed out operation - nil
+// while it is running.
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("request to Keeper Secrets Manager stopped: %w", context.Cause(ctx))
+	}
+	return nil
+}
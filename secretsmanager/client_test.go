@@ -0,0 +1,230 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func newTestFakeVault(t *testing.T) (*fakeVault, string) {
+	t.Helper()
+	delay := throttleRetryDelay
+	throttleRetryDelay = time.Millisecond
+	t.Cleanup(func() { throttleRetryDelay = delay })
+
+	vault := newFakeVault()
+	folderUid := vault.AddFolder("", "shared")
+	return vault, folderUid
+}
+
+func TestFakeVaultGetRecord(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	subfolderUid := vault.AddFolder(folderUid, "sub")
+	uid := vault.AddRecord(subfolderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+
+	record, err := getRecord(ctx, uid, "", vault)
+	if err != nil {
+		t.Fatalf("getRecord by UID: %v", err)
+	}
+	if record.Title() != "web" || record.Type() != "login" {
+		t.Errorf("unexpected record: title=%q type=%q", record.Title(), record.Type())
+	}
+	if record.FolderUid() != folderUid || record.InnerFolderUid() != subfolderUid {
+		t.Errorf("unexpected folders: shared=%q inner=%q", record.FolderUid(), record.InnerFolderUid())
+	}
+
+	if record, err = getRecord(ctx, "*", "web", vault); err != nil || record.Uid != uid {
+		t.Fatalf("getRecord by title: %v", err)
+	}
+	if _, err = getRecord(ctx, "*", "missing", vault); err == nil {
+		t.Error("expected an error for a missing title")
+	}
+
+	values, err := getNotation(ctx, vault, uid+"/field/login")
+	if err != nil {
+		t.Fatalf("getNotation: %v", err)
+	}
+	if len(values) != 1 || values[0] != "admin" {
+		t.Errorf("getNotation = %v, want [admin]", values)
+	}
+}
+
+func TestFakeVaultThrottledRetries(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+
+	record, err := getRecord(ctx, uid, "", vault)
+	if err != nil {
+		t.Fatalf("getRecord: %v", err)
+	}
+	record.SetTitle("renamed")
+
+	vault.throttle = 2
+	if err := saveRecord(ctx, record, vault); err != nil {
+		t.Fatalf("saveRecord after throttling: %v", err)
+	}
+	if vault.calls["Save"] != 3 {
+		t.Errorf("Save called %d times, want 3", vault.calls["Save"])
+	}
+	if got := vault.Record(uid)["title"]; got != "renamed" {
+		t.Errorf("title = %v, want renamed", got)
+	}
+
+	// stale revision is rejected
+	if err := saveRecord(ctx, record, vault); err == nil {
+		t.Error("expected an error saving an outdated revision")
+	}
+
+	vault.throttle = MaxThrottledRetries
+	if _, err := getSecrets(ctx, vault, []string{uid}); !isThrottled(err) {
+		t.Errorf("expected throttled error after %d retries, got %v", MaxThrottledRetries, err)
+	}
+}
+
+func TestThrottledRetriesStopWhenCanceled(t *testing.T) {
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	throttleRetryDelay = time.Hour
+
+	vault.throttle = MaxThrottledRetries
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, err := getSecrets(ctx, vault, []string{uid}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Minute {
+		t.Errorf("retries kept waiting %s after the deadline", elapsed)
+	}
+	if vault.calls["GetSecrets"] != 1 {
+		t.Errorf("GetSecrets called %d times, want 1", vault.calls["GetSecrets"])
+	}
+
+	// canceled operations make no more requests
+	vault.throttle = 0
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := createFolder(canceled, folderUid, "team", vault); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled error, got %v", err)
+	}
+	if vault.calls["CreateFolder"] != 0 {
+		t.Errorf("CreateFolder called %d times after cancel", vault.calls["CreateFolder"])
+	}
+}
+
+func TestResourceTimeouts(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	for name, r := range Provider().ResourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Create == nil || r.Timeouts.Read == nil || r.Timeouts.Update == nil || r.Timeouts.Delete == nil {
+			t.Errorf("%s has no timeouts", name)
+		}
+	}
+
+	// the timeout of the operation is passed to the helpers
+	r := Provider().ResourcesMap["secretsmanager_login"]
+	config := map[string]interface{}{"folder_uid": folderUid, "title": "web", "timeouts": []interface{}{map[string]interface{}{"create": "1ms"}}}
+	vault.throttle = MaxThrottledRetries
+	throttleRetryDelay = time.Hour
+	diff, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), providerMeta{client: vault})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if _, diags := r.Apply(ctx, nil, diff, providerMeta{client: vault}); !diags.HasError() {
+		t.Fatal("expected the create to time out")
+	}
+	if vault.calls["CreateSecretWithRecordDataUidAndOptions"] > 1 {
+		t.Errorf("create kept retrying after the timeout")
+	}
+}
+
+func TestFakeVaultFileContent(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "file", "title": "cert"})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+
+	record, err := getRecord(ctx, uid, "", vault)
+	if err != nil {
+		t.Fatalf("getRecord: %v", err)
+	}
+	files := getFileItemsData(record.Files)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	file := files[0].(map[string]interface{})
+	if file["name"] != "cert.pem" || file["content_base64"] != base64.StdEncoding.EncodeToString([]byte("certificate")) {
+		t.Errorf("unexpected file data: %v", file)
+	}
+}
+
+func TestResourceLoginFakeVault(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	meta := providerMeta{client: vault}
+
+	d := schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{
+		"folder_uid": folderUid,
+		"title":      "web",
+		"login":      []interface{}{map[string]interface{}{"value": "admin"}},
+	})
+	if diags := resourceLoginCreate(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	uid := d.Id()
+	if vault.Record(uid) == nil {
+		t.Fatalf("record %q not created", uid)
+	}
+
+	if diags := resourceLoginRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if got := d.Get("login.0.value").(string); got != "admin" {
+		t.Errorf("login = %q, want admin", got)
+	}
+
+	if diags := resourceLoginDelete(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if vault.Record(uid) != nil {
+		t.Error("record not deleted")
+	}
+}
+
+func TestResourceFolderFakeVault(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	meta := providerMeta{client: vault}
+
+	d := schema.TestResourceDataRaw(t, resourceFolder().Schema, map[string]interface{}{
+		"parent_uid": folderUid,
+		"name":       "team",
+	})
+	if diags := resourceFolderCreate(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	vault.AddRecord(d.Id(), map[string]interface{}{"type": "login", "title": "inside"})
+
+	if diags := resourceFolderDelete(ctx, d, meta); !diags.HasError() {
+		t.Error("expected an error deleting a non-empty folder without force_delete")
+	}
+
+	d.Set("force_delete", true)
+	if diags := resourceFolderDelete(ctx, d, meta); diags.HasError() {
+		t.Fatalf("forced delete: %v", diags)
+	}
+	if folders, _ := vault.GetFolders(); len(folders) != 1 {
+		t.Errorf("expected only the shared folder left, got %d folders", len(folders))
+	}
+}
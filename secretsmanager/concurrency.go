@@ -0,0 +1,222 @@
+package secretsmanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+	"golang.org/x/sync/singleflight"
+)
+
+const maxConcurrentRequestsDescription = "Maximum number of Keeper Secrets Manager API requests the provider runs at the same time, " +
+	"across all resources, data sources and ephemeral resources using the same credential. " +
+	"`0` (default) doesn't limit them. Can also be sourced from the `KEEPER_MAX_CONCURRENT_REQUESTS` environment variable."
+
+// maxConcurrentRequests returns the setting, falling back to the environment.
+func maxConcurrentRequests(value int) (int, error) {
+	if value == 0 {
+		if env := strings.TrimSpace(envDefault("KEEPER_MAX_CONCURRENT_REQUESTS")); env != "" {
+			n, err := strconv.Atoi(env)
+			if err != nil {
+				return 0, fmt.Errorf("invalid KEEPER_MAX_CONCURRENT_REQUESTS '%s': %w", env, err)
+			}
+			value = n
+		}
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("max_concurrent_requests must be 0 or more, got %d", value)
+	}
+	return value, nil
+}
+
+// requestGate coalesces identical in-flight reads and bounds the concurrent
+// requests of one credential. Both providers share it through requestGates.
+type requestGate struct {
+	group singleflight.Group
+	// slots is nil when requests aren't limited
+	slots chan struct{}
+	// generation counts completed writes - a read never joins a read started
+	// before a write that completed ahead of it
+	generation atomic.Uint64
+}
+
+var (
+	requestGatesMu sync.Mutex
+	requestGates   = map[string]*requestGate{}
+)
+
+// requestGateFor returns the gate of the credential and limit.
+func requestGateFor(clientId string, limit int) *requestGate {
+	key := clientId + ":" + strconv.Itoa(limit)
+	requestGatesMu.Lock()
+	defer requestGatesMu.Unlock()
+	if gate, found := requestGates[key]; found {
+		return gate
+	}
+	gate := &requestGate{}
+	if limit > 0 {
+		gate.slots = make(chan struct{}, limit)
+	}
+	requestGates[key] = gate
+	return gate
+}
+
+func (g *requestGate) acquire() {
+	if g.slots != nil {
+		g.slots <- struct{}{}
+	}
+}
+
+func (g *requestGate) release() {
+	if g.slots != nil {
+		<-g.slots
+	}
+}
+
+// readKey is the key of a coalesced read in the current write generation.
+func (g *requestGate) readKey(read string) string {
+	return strconv.FormatUint(g.generation.Load(), 10) + ":" + read
+}
+
+// wrote starts a new write generation once a write completed, failed or not.
+func (g *requestGate) wrote() {
+	g.generation.Add(1)
+}
+
+// sharedClient is the client shared by all resources, data sources and
+// ephemeral resources of a provider configuration - Terraform calls it from
+// concurrent goroutines (up to -parallelism). It is safe for concurrent use:
+// identical in-flight GetSecrets and GetFolders calls are coalesced into one
+// request unless a write completed since the request started, every request waits for a slot of max_concurrent_requests, and
+// callers of coalesced calls get their own copies of the results so they can
+// change them. File downloads go directly through the records and are not
+// limited.
+type sharedClient struct {
+	ksmClient
+	gate *requestGate
+}
+
+func (c *sharedClient) unwrap() ksmClient { return c.ksmClient }
+
+// configureSharedClient wraps the client of the credential.
+func configureSharedClient(client ksmClient, config core.IKeyValueStorage, limit int) ksmClient {
+	return &sharedClient{ksmClient: client, gate: requestGateFor(config.Get(core.KEY_CLIENT_ID), limit)}
+}
+
+func (c *sharedClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	v, err, shared := c.gate.group.Do(c.gate.readKey("GetSecrets:"+strings.Join(uids, ",")), func() (interface{}, error) {
+		c.gate.acquire()
+		defer c.gate.release()
+		return c.ksmClient.GetSecrets(uids)
+	})
+	records, _ := v.([]*core.Record)
+	if shared {
+		records = copyRecords(records)
+	}
+	return records, err
+}
+
+func (c *sharedClient) GetFolders() ([]*core.KeeperFolder, error) {
+	v, err, shared := c.gate.group.Do(c.gate.readKey("GetFolders"), func() (interface{}, error) {
+		c.gate.acquire()
+		defer c.gate.release()
+		return c.ksmClient.GetFolders()
+	})
+	folders, _ := v.([]*core.KeeperFolder)
+	if shared {
+		folders = copyFolders(folders)
+	}
+	return folders, err
+}
+
+func (c *sharedClient) Save(record *core.Record) error {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.Save(record)
+}
+
+func (c *sharedClient) DeleteSecrets(recordUids []string) (map[string]string, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.DeleteSecrets(recordUids)
+}
+
+func (c *sharedClient) CreateSecretWithRecordDataUidAndOptions(recUid string, createOptions *core.CreateOptions, recordData *core.RecordCreate, folders []*core.KeeperFolder) (string, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.CreateSecretWithRecordDataUidAndOptions(recUid, createOptions, recordData, folders)
+}
+
+func (c *sharedClient) CreateFolder(createOptions core.CreateOptions, folderName string, folders []*core.KeeperFolder) (string, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.CreateFolder(createOptions, folderName, folders)
+}
+
+func (c *sharedClient) UpdateFolder(folderUid, folderName string, folders []*core.KeeperFolder) error {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.UpdateFolder(folderUid, folderName, folders)
+}
+
+func (c *sharedClient) DeleteFolder(folderUids []string, forceDeletion bool) (map[string]string, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.DeleteFolder(folderUids, forceDeletion)
+}
+
+func (c *sharedClient) GetNotation(notation string) ([]interface{}, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	return c.ksmClient.GetNotation(notation)
+}
+
+func (c *sharedClient) UploadFile(record *core.Record, file *core.KeeperFileUpload) (string, error) {
+	c.gate.acquire()
+	defer c.gate.release()
+	defer c.gate.wrote()
+	return c.ksmClient.UploadFile(record, file)
+}
+
+// copyRecords copies the records so callers sharing a coalesced result can
+// change their records and files independently.
+func copyRecords(records []*core.Record) []*core.Record {
+	if records == nil {
+		return nil
+	}
+	copies := make([]*core.Record, 0, len(records))
+	for _, r := range records {
+		c := *r
+		if r.RecordDict != nil {
+			c.RecordDict = core.JsonToDict(r.RawJson)
+		}
+		c.Files = make([]*core.KeeperFile, 0, len(r.Files))
+		for _, f := range r.Files {
+			file := *f
+			c.Files = append(c.Files, &file)
+		}
+		copies = append(copies, &c)
+	}
+	return copies
+}
+
+func copyFolders(folders []*core.KeeperFolder) []*core.KeeperFolder {
+	if folders == nil {
+		return nil
+	}
+	copies := make([]*core.KeeperFolder, 0, len(folders))
+	for _, f := range folders {
+		c := *f
+		copies = append(copies, &c)
+	}
+	return copies
+}
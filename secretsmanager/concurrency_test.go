@@ -0,0 +1,249 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// slowClient holds every call for a while and tracks the calls in flight.
+type slowClient struct {
+	ksmClient
+	delay       time.Duration
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+}
+
+func (c *slowClient) enter() {
+	c.mu.Lock()
+	c.calls++
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+	time.Sleep(c.delay)
+}
+
+func (c *slowClient) leave() {
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+}
+
+func (c *slowClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	c.enter()
+	defer c.leave()
+	return c.ksmClient.GetSecrets(uids)
+}
+
+func (c *slowClient) GetFolders() ([]*core.KeeperFolder, error) {
+	c.enter()
+	defer c.leave()
+	return c.ksmClient.GetFolders()
+}
+
+func (c *slowClient) Save(record *core.Record) error {
+	c.enter()
+	defer c.leave()
+	return c.ksmClient.Save(record)
+}
+
+// blockingClient holds the first GetSecrets call, after it read the vault,
+// until released.
+type blockingClient struct {
+	ksmClient
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingClient) GetSecrets(uids []string) ([]*core.Record, error) {
+	first := false
+	c.once.Do(func() { first = true })
+	records, err := c.ksmClient.GetSecrets(uids)
+	if first {
+		close(c.started)
+		<-c.release
+	}
+	return records, err
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	t.Setenv("KEEPER_MAX_CONCURRENT_REQUESTS", "4")
+	if n, err := maxConcurrentRequests(0); err != nil || n != 4 {
+		t.Errorf("expected the environment default 4, got %d: %v", n, err)
+	}
+	if n, err := maxConcurrentRequests(2); err != nil || n != 2 {
+		t.Errorf("expected the setting to win over the environment, got %d: %v", n, err)
+	}
+	t.Setenv("KEEPER_MAX_CONCURRENT_REQUESTS", "many")
+	if _, err := maxConcurrentRequests(0); err == nil {
+		t.Error("expected an error for an invalid environment value")
+	}
+	if _, err := maxConcurrentRequests(-1); err == nil {
+		t.Error("expected an error for a negative limit")
+	}
+	if requestGateFor("client-a", 2) != requestGateFor("client-a", 2) || requestGateFor("client-a", 2) == requestGateFor("client-b", 2) {
+		t.Error("expected one gate per credential and limit")
+	}
+}
+
+func TestSharedClientCoalescesReads(t *testing.T) {
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web", "notes": "shared"})
+	backend := &slowClient{ksmClient: vault, delay: 50 * time.Millisecond}
+	client := &sharedClient{ksmClient: backend, gate: &requestGate{}}
+
+	const callers = 50
+	results := make([][]*core.Record, callers)
+	folders := make([][]*core.KeeperFolder, callers)
+	errs := make(chan error, 2*callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			records, err := client.GetSecrets([]string{uid})
+			if err != nil {
+				errs <- err
+			}
+			results[i] = records
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			f, err := client.GetFolders()
+			if err != nil {
+				errs <- err
+			}
+			folders[i] = f
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent read: %v", err)
+	}
+	if backend.calls >= 2*callers {
+		t.Errorf("expected identical reads to be coalesced, got %d backend calls for %d reads", backend.calls, 2*callers)
+	}
+
+	// callers of a coalesced read don't share records
+	for i := range results {
+		if len(results[i]) != 1 || results[i][0].Notes() != "shared" || len(folders[i]) == 0 {
+			t.Fatalf("unexpected result %d: %v %v", i, results[i], folders[i])
+		}
+	}
+	results[0][0].SetNotes("changed")
+	for i := 1; i < callers; i++ {
+		if results[i][0] == results[0][0] {
+			continue
+		}
+		if results[i][0].Notes() != "shared" {
+			t.Fatalf("a change to one caller's record is seen by caller %d", i)
+		}
+	}
+}
+
+func TestSharedClientBoundsConcurrency(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uids := []string{}
+	for i := 0; i < 20; i++ {
+		uids = append(uids, vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": fmt.Sprintf("web %d", i), "notes": ""}))
+	}
+	backend := &slowClient{ksmClient: vault, delay: 10 * time.Millisecond}
+	client := configureSharedClient(backend, core.NewMemoryKeyValueStorage(fakeCredential()), 3)
+	// resources fetch and save through the other wrappers of the provider
+	client = configureWritePolicy(client, writePolicy{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(uids))
+	for _, uid := range uids {
+		wg.Add(2)
+		go func(uid string) {
+			defer wg.Done()
+			if _, err := getSecrets(ctx, client, []string{uid}); err != nil {
+				errs <- err
+			}
+		}(uid)
+		go func(uid string) {
+			defer wg.Done()
+			records, err := getSecrets(ctx, client, []string{uid})
+			if err == nil {
+				records[0].SetNotes("updated")
+				err = saveRecord(ctx, records[0], client)
+			}
+			if err != nil {
+				errs <- err
+			}
+		}(uid)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent call: %v", err)
+	}
+	if backend.maxInFlight > 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", backend.maxInFlight)
+	}
+	if backend.maxInFlight < 2 {
+		t.Errorf("expected requests to run concurrently, got %d in flight", backend.maxInFlight)
+	}
+	for _, uid := range uids {
+		records, err := vault.GetSecrets([]string{uid})
+		if err != nil || len(records) != 1 || records[0].Notes() != "updated" {
+			t.Errorf("record %s wasn't updated: %v", uid, err)
+		}
+	}
+}
+
+// TestSharedClientReadAfterWrite verifies a read issued after a write doesn't
+// join a read started before it and returns the written record.
+func TestSharedClientReadAfterWrite(t *testing.T) {
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web", "notes": "before"})
+	backend := &blockingClient{ksmClient: vault, started: make(chan struct{}), release: make(chan struct{})}
+	client := &sharedClient{ksmClient: backend, gate: &requestGate{}}
+
+	stale := make(chan []*core.Record, 1)
+	go func() {
+		records, _ := client.GetSecrets([]string{uid})
+		stale <- records
+	}()
+	<-backend.started
+
+	records, err := vault.GetSecrets([]string{uid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records[0].SetNotes("after")
+	if err := client.Save(records[0]); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	fresh := make(chan []*core.Record, 1)
+	go func() {
+		records, _ := client.GetSecrets([]string{uid})
+		fresh <- records
+	}()
+	select {
+	case records := <-fresh:
+		if len(records) != 1 || records[0].Notes() != "after" {
+			t.Errorf("read after write = %v, want the written record", records)
+		}
+	case <-time.After(time.Second):
+		t.Error("read after write joined the read started before the write")
+	}
+
+	close(backend.release)
+	if records := <-stale; len(records) != 1 || records[0].Notes() != "before" {
+		t.Errorf("read before write = %v", records)
+	}
+}
@@ -124,15 +124,16 @@ func dataSourceAddress() *schema.Resource {
 
 func dataSourceAddressRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	path := strings.TrimSpace(d.Get("path").(string))
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(path, title, client)
+	secret, fallback, err := readRecord(ctx, path, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	diags = append(diags, fallback.diagnostics()...)
 
 	dataSourceType := "address"
 	recordType := secret.Type()
@@ -232,15 +232,16 @@ func dataSourceBankAccount() *schema.Resource {
 
 func dataSourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	path := strings.TrimSpace(d.Get("path").(string))
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(path, title, client)
+	secret, fallback, err := readRecord(ctx, path, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	diags = append(diags, fallback.diagnostics()...)
 
 	dataSourceType := "bankAccount"
 	recordType := secret.Type()
@@ -282,7 +283,7 @@ func dataSourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m in
 	// - external cardRef UID present but its record may not be shared to the app or externally deleted
 	if cardRef := strings.TrimSpace(secret.GetFieldValueByType("cardRef")); cardRef != "" {
 		cardItems := []interface{}{map[string]interface{}{"uid": cardRef}}
-		if secretCardRefs, err := getSecrets(client, []string{cardRef}); err == nil && len(secretCardRefs) > 0 {
+		if secretCardRefs, _, err := readSecrets(ctx, client, []string{cardRef}); err == nil && len(secretCardRefs) > 0 {
 			cardItems = getCardRefItemData(secretCardRefs[0], cardRef)
 		}
 		if err = d.Set("card_ref", cardItems); err != nil {
@@ -192,15 +192,16 @@ func dataSourceContact() *schema.Resource {
 
 func dataSourceContactRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	path := strings.TrimSpace(d.Get("path").(string))
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(path, title, client)
+	secret, fallback, err := readRecord(ctx, path, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	diags = append(diags, fallback.diagnostics()...)
 
 	dataSourceType := "contact"
 	recordType := secret.Type()
@@ -238,7 +239,7 @@ func dataSourceContactRead(ctx context.Context, d *schema.ResourceData, m interf
 	// - external addredsRef UID present but its record may not be shared to the app or externally deleted
 	if addressRef := strings.TrimSpace(secret.GetFieldValueByType("addressRef")); addressRef != "" {
 		addrItems := []interface{}{map[string]interface{}{"uid": addressRef}}
-		if secretAddrRefs, err := getSecrets(client, []string{addressRef}); err == nil && len(secretAddrRefs) > 0 {
+		if secretAddrRefs, _, err := readSecrets(ctx, client, []string{addressRef}); err == nil && len(secretAddrRefs) > 0 {
 			addrItems = getAddressRefItemData(secretAddrRefs[0], addressRef)
 		}
 		if err = d.Set("address_ref", addrItems); err != nil {
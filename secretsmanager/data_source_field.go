@@ -2,7 +2,6 @@ package secretsmanager
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -26,7 +25,18 @@ func dataSourceField() *schema.Resource {
 			"value": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "The value of the secret field.",
+				Description: fieldValueDescription,
+			},
+			"values": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: fieldValuesDescription,
+			},
+			"value_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: fieldValueJsonDescription,
 			},
 		},
 	}
@@ -34,7 +44,7 @@ func dataSourceField() *schema.Resource {
 
 func dataSourceFieldRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	path := strings.TrimSpace(d.Get("path").(string))
@@ -43,10 +53,11 @@ func dataSourceFieldRead(ctx context.Context, d *schema.ResourceData, m interfac
 	// find by title requested
 	if title != "" && strings.Contains(path, "*") {
 		uids := []string{}
-		records, err := getSecrets(client, []string{})
+		records, fallback, err := readSecrets(ctx, client, []string{})
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		diags = append(diags, fallback.diagnostics()...)
 		for _, r := range records {
 			if r.Title() == title {
 				uids = append(uids, r.Uid)
@@ -62,19 +73,25 @@ func dataSourceFieldRead(ctx context.Context, d *schema.ResourceData, m interfac
 		}
 	}
 
-	value, err := getNotation(client, path)
+	value, fallback, err := readNotation(ctx, client, path)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-
-	strValue := ""
-	if len(value) == 1 {
-		strValue = fmt.Sprintf("%v", value[0])
-	} else {
-		strValue = fmt.Sprintf("%v", value)
+	if len(diags) == 0 {
+		diags = append(diags, fallback.diagnostics()...)
 	}
 
-	if err = d.Set("value", strValue); err != nil {
+	result, err := newNotationResult(value)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("value", result.Value); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("values", result.Values); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("value_json", result.ValueJson); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -75,24 +75,34 @@ func dataSourceFile() *schema.Resource {
 							Computed:    true,
 							Description: "The file content (base64).",
 						},
+						"sha256": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: fileSha256Description,
+						},
 					},
 				},
 			},
+			"include_file_content": schemaIncludeFileContent(),
+			"include_file_hash":    schemaIncludeFileHash(),
+			"file_name_filter":     schemaFileNameFilter(),
+			"max_file_size":        schemaMaxFileSize(),
 		},
 	}
 }
 
 func dataSourceFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	path := strings.TrimSpace(d.Get("path").(string))
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(path, title, client)
+	secret, fallback, err := readRecord(ctx, path, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	diags = append(diags, fallback.diagnostics()...)
 
 	dataSourceType := "file"
 	recordType := secret.Type()
@@ -109,7 +119,11 @@ func dataSourceFileRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	fileItems := getFileItemsData(secret.Files)
+	fileOptions, err := fileContentOptionsFromSchema(d, provider)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	fileItems := getFileItemsDataWithOptions(secret.Files, fileOptions)
 	if err := d.Set("file_ref", fileItems); err != nil {
 		return diag.FromErr(err)
 	}
@@ -50,13 +50,13 @@ func dataSourceFolder() *schema.Resource {
 
 func dataSourceFolderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	parentUid := strings.TrimSpace(d.Get("parent_uid").(string))
 	uid := strings.TrimSpace(d.Get("uid").(string))
 	name := strings.TrimSpace(d.Get("name").(string))
-	folders, err := findFolder(parentUid, uid, name, client)
+	folders, err := findFolder(ctx, parentUid, uid, name, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -49,10 +49,10 @@ func dataSourceFolders() *schema.Resource {
 
 func dataSourceFoldersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
-	folders, err := getFolders(client)
+	folders, err := getFolders(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
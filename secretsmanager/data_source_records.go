@@ -47,6 +47,10 @@ func dataSourceRecords() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"include_file_content": schemaIncludeFileContent(),
+			"include_file_hash":    schemaIncludeFileHash(),
+			"file_name_filter":     schemaFileNameFilter(),
+			"max_file_size":        schemaMaxFileSize(),
 			"records": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -117,6 +121,11 @@ func dataSourceRecords() *schema.Resource {
 										Computed:    true,
 										Description: "The file content (base64)",
 									},
+									"sha256": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: fileSha256Description,
+									},
 								},
 							},
 						},
@@ -137,44 +146,107 @@ func dataSourceRecords() *schema.Resource {
 
 func dataSourceRecordsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
-	// Get UIDs, titles, and title patterns from config
-	uidsRaw := d.Get("uids").([]interface{})
-	titlesRaw := d.Get("titles").([]interface{})
-	titlePatternsRaw := d.Get("title_patterns").([]interface{})
+	fileOptions, err := fileContentOptionsFromSchema(d, provider)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	secrets, fallback, err := findRecords(ctx, client,
+		stringList(d.Get("uids")), stringList(d.Get("titles")), stringList(d.Get("title_patterns")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	diags = append(diags, fallback.diagnostics()...)
 
-	// Validate that at least one is provided
-	if len(uidsRaw) == 0 && len(titlesRaw) == 0 && len(titlePatternsRaw) == 0 {
-		return diag.Errorf("at least one of 'uids', 'titles', or 'title_patterns' must be provided")
+	// Convert records to Terraform schema format
+	recordsList := make([]interface{}, len(secrets))
+	recordsMap := make(map[string]interface{})
+
+	for i, secret := range secrets {
+		record := getRecordItemData(secret, fileOptions)
+		recordsList[i] = record
+
+		// Store JSON-encoded record in map for UID-based access
+		// This allows users to decode and access the full record structure
+		if jsonData, err := json.Marshal(record); err == nil {
+			recordsMap[secret.Uid] = string(jsonData)
+		}
+	}
+
+	// Set the records list in the data source
+	if err := d.Set("records", recordsList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Set the records map for UID-based access
+	if err := d.Set("records_by_uid", recordsMap); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Generate a consistent ID for this data source
+	// Use a hash of sorted UIDs for consistency
+	allIds := make([]string, 0, len(secrets))
+	for _, record := range secrets {
+		allIds = append(allIds, record.Uid)
+	}
+	sort.Strings(allIds)
+
+	h := sha256.New()
+	h.Write([]byte(strings.Join(allIds, ",")))
+	d.SetId(fmt.Sprintf("%x", h.Sum(nil)))
+
+	return diags
+}
+
+// stringList converts a list attribute of the SDKv2 schema to strings.
+func stringList(data interface{}) []string {
+	items, _ := data.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, _ := item.(string)
+		values = append(values, value)
 	}
+	return values
+}
 
-	// Convert to string slices
-	uids := make([]string, len(uidsRaw))
-	for i, uid := range uidsRaw {
-		uids[i] = strings.TrimSpace(uid.(string))
+// getRecordItemData returns the record as an item of the records list -
+// the same shape in secretsmanager_records and its ephemeral resources.
+func getRecordItemData(secret *core.Record, fileOptions fileContentOptions) map[string]interface{} {
+	return map[string]interface{}{
+		"uid":      secret.Uid,
+		"type":     secret.Type(),
+		"title":    secret.Title(),
+		"notes":    secret.Notes(),
+		"fields":   getFieldItemsData(secret.RecordDict, "fields"),
+		"custom":   getFieldItemsData(secret.RecordDict, "custom"),
+		"file_ref": getFileItemsDataWithOptions(secret.Files, fileOptions),
 	}
+}
 
-	titles := make([]string, len(titlesRaw))
-	for i, title := range titlesRaw {
-		titles[i] = strings.TrimSpace(title.(string))
+// findRecords returns the records matching any of the UIDs, exact titles or
+// title patterns - an error when a requested UID or title isn't found.
+func findRecords(ctx context.Context, client ksmClient, uids, titles, patterns []string) ([]*core.Record, *cacheFallback, error) {
+	// Validate that at least one is provided
+	if len(uids) == 0 && len(titles) == 0 && len(patterns) == 0 {
+		return nil, nil, fmt.Errorf("at least one of 'uids', 'titles', or 'title_patterns' must be provided")
 	}
+	uids = trimStrings(uids)
+	titles = trimStrings(titles)
 
 	// Compile regex patterns with complexity validation
-	titlePatterns := make([]*regexp.Regexp, len(titlePatternsRaw))
-	for i, pattern := range titlePatternsRaw {
-		patternStr := strings.TrimSpace(pattern.(string))
-
+	titlePatterns := make([]*regexp.Regexp, len(patterns))
+	for i, patternStr := range trimStrings(patterns) {
 		// Validate pattern length to prevent ReDoS attacks
 		if len(patternStr) > maxRegexPatternLength {
-			return diag.Errorf("regex pattern exceeds maximum length of %d characters (got %d): '%s'",
+			return nil, nil, fmt.Errorf("regex pattern exceeds maximum length of %d characters (got %d): '%s'",
 				maxRegexPatternLength, len(patternStr), patternStr)
 		}
 
 		re, err := regexp.Compile(patternStr)
 		if err != nil {
-			return diag.Errorf("invalid regex pattern '%s': %v", patternStr, err)
+			return nil, nil, fmt.Errorf("invalid regex pattern '%s': %v", patternStr, err)
 		}
 		titlePatterns[i] = re
 	}
@@ -183,19 +255,18 @@ func dataSourceRecordsRead(ctx context.Context, d *schema.ResourceData, m interf
 	// const maxBatchSize = 500
 	// totalRecords := len(uids) + len(titles)
 	// if totalRecords > maxBatchSize {
-	//     return diag.Errorf("batch size exceeds maximum of %d records (requested %d)", maxBatchSize, totalRecords)
+	//     return nil, nil, fmt.Errorf("batch size exceeds maximum of %d records (requested %d)", maxBatchSize, totalRecords)
 	// }
 
 	var secrets []*core.Record
-	var err error
 
 	// Optimization: If we have titles or patterns, we need to fetch all records anyway
 	// So we can filter both UIDs, titles, and patterns from the same result set
 	if len(titles) > 0 || len(titlePatterns) > 0 {
 		// Fetch all records once
-		allSecrets, err := client.GetSecrets([]string{})
+		allSecrets, fallback, err := readSecrets(ctx, client, []string{})
 		if err != nil {
-			return diag.Errorf("failed to fetch all records: %v", err)
+			return nil, nil, fmt.Errorf("failed to fetch all records: %v", err)
 		}
 
 		// Create maps for efficient lookup
@@ -237,92 +308,46 @@ func dataSourceRecordsRead(ctx context.Context, d *schema.ResourceData, m interf
 		// Check for missing UIDs
 		for _, uid := range uids {
 			if !foundUids[uid] {
-				return diag.Errorf("record not found - UID: %s", uid)
+				return nil, nil, fmt.Errorf("record not found - UID: %s", uid)
 			}
 		}
 
 		// Check for missing titles
 		for _, title := range titles {
 			if !foundTitles[title] {
-				return diag.Errorf("record not found - title: %s", title)
-			}
-		}
-	} else {
-		// Only UIDs provided - efficient batch fetch
-		secrets, err = client.GetSecrets(uids)
-		if err != nil {
-			return diag.Errorf("failed to fetch records: %v", err)
-		}
-
-		// Validate that we got all requested records
-		if len(secrets) != len(uids) {
-			// Find missing UIDs
-			foundUids := make(map[string]bool)
-			for _, record := range secrets {
-				foundUids[record.Uid] = true
+				return nil, nil, fmt.Errorf("record not found - title: %s", title)
 			}
-			for _, uid := range uids {
-				if !foundUids[uid] {
-					return diag.Errorf("record not found - UID: %s", uid)
-				}
-			}
-		}
-	}
-
-	// Convert records to Terraform schema format
-	recordsList := make([]interface{}, len(secrets))
-	recordsMap := make(map[string]interface{})
-
-	for i, secret := range secrets {
-		record := make(map[string]interface{})
-
-		record["uid"] = secret.Uid
-		record["type"] = secret.Type()
-		record["title"] = secret.Title()
-		record["notes"] = secret.Notes()
-
-		// Process fields
-		fieldItems := getFieldItemsData(secret.RecordDict, "fields")
-		record["fields"] = fieldItems
-
-		// Process custom fields
-		customItems := getFieldItemsData(secret.RecordDict, "custom")
-		record["custom"] = customItems
-
-		// Process file references
-		fileItems := getFileItemsData(secret.Files)
-		record["file_ref"] = fileItems
-
-		recordsList[i] = record
-
-		// Store JSON-encoded record in map for UID-based access
-		// This allows users to decode and access the full record structure
-		if jsonData, err := json.Marshal(record); err == nil {
-			recordsMap[secret.Uid] = string(jsonData)
 		}
+		return secrets, fallback, nil
 	}
 
-	// Set the records list in the data source
-	if err := d.Set("records", recordsList); err != nil {
-		return diag.FromErr(err)
+	// Only UIDs provided - efficient batch fetch
+	secrets, fallback, err := readSecrets(ctx, client, uids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch records: %v", err)
 	}
 
-	// Set the records map for UID-based access
-	if err := d.Set("records_by_uid", recordsMap); err != nil {
-		return diag.FromErr(err)
+	// Validate that we got all requested records
+	if len(secrets) != len(uids) {
+		// Find missing UIDs
+		foundUids := make(map[string]bool)
+		for _, record := range secrets {
+			foundUids[record.Uid] = true
+		}
+		for _, uid := range uids {
+			if !foundUids[uid] {
+				return nil, nil, fmt.Errorf("record not found - UID: %s", uid)
+			}
+		}
 	}
+	return secrets, fallback, nil
+}
 
-	// Generate a consistent ID for this data source
-	// Use a hash of sorted UIDs for consistency
-	allIds := make([]string, 0, len(secrets))
-	for _, record := range secrets {
-		allIds = append(allIds, record.Uid)
+// trimStrings returns a copy of the values without leading and trailing spaces.
+func trimStrings(values []string) []string {
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.TrimSpace(value)
 	}
-	sort.Strings(allIds)
-
-	h := sha256.New()
-	h.Write([]byte(strings.Join(allIds, ",")))
-	d.SetId(fmt.Sprintf("%x", h.Sum(nil)))
-
-	return diags
+	return trimmed
 }
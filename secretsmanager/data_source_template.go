@@ -0,0 +1,158 @@
+package secretsmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// Template settings - the same attributes in the data source and the
+// ephemeral resource.
+const (
+	templateDescription = "Text with `keeper://` references to replace, ex. `password: keeper://<record UID>/field/password`. " +
+		"A reference is a Keeper notation (record UID or title) and ends at whitespace, a quote or `<`/`>` - " +
+		"enclose references with whitespace in `${...}`, ex. `$${keeper://My Database/field/password}` in a Terraform string."
+	templateRenderedDescription   = "The template with every reference replaced by the value it selects - complex values JSON-encoded."
+	templateReferencesDescription = "The distinct references found in the template."
+)
+
+// templateReferencePattern matches the keeper:// references of a template -
+// either delimited ${keeper://...}, which may contain whitespace, or bare.
+var templateReferencePattern = regexp.MustCompile("\\$\\{keeper://[^}]*\\}|keeper://[^\\s\"'`<>]*")
+
+// recordUidPattern matches record UIDs - other record tokens are titles.
+var recordUidPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{22}$`)
+
+func dataSourceTemplate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTemplateRead,
+		Schema: map[string]*schema.Schema{
+			"template": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: templateDescription,
+			},
+			"rendered": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: templateRenderedDescription,
+			},
+			"references": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: templateReferencesDescription,
+			},
+		},
+	}
+}
+
+func dataSourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	provider := m.(providerMeta)
+	client := provider.client
+
+	template := d.Get("template").(string)
+	rendered, references, fallback, err := renderTemplate(ctx, client, template)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	diags := fallback.diagnostics()
+
+	if err = d.Set("rendered", rendered); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("references", references); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(template))))
+
+	return diags
+}
+
+// renderTemplate replaces the keeper:// references of the template with the
+// values they select and returns the distinct references. All records are
+// fetched with a single getSecrets call - only the referenced UIDs when no
+// reference uses a record title.
+func renderTemplate(ctx context.Context, client ksmClient, template string) (string, []string, *cacheFallback, error) {
+	references := []string{}
+	uids := []string{}
+	titles := map[string]bool{}
+	seen := map[string]bool{}
+	for _, match := range templateReferencePattern.FindAllString(template, -1) {
+		reference := templateReference(match)
+		if seen[reference] {
+			continue
+		}
+		seen[reference] = true
+		references = append(references, reference)
+
+		parsed, err := core.ParseNotation(reference)
+		if err != nil || len(parsed) < 3 || parsed[1] == nil || parsed[1].Text == nil {
+			return "", nil, nil, fmt.Errorf("invalid reference '%s' - enclose references with whitespace in ${...}", reference)
+		}
+		if token := parsed[1].Text.Text; recordUidPattern.MatchString(token) {
+			uids = append(uids, token)
+		} else {
+			titles[token] = true
+		}
+	}
+	if len(references) == 0 {
+		return template, references, nil, nil
+	}
+
+	fetchUids := uids
+	if len(titles) > 0 {
+		fetchUids = []string{}
+	}
+	records, fallback, err := readSecrets(ctx, client, fetchUids)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	for title := range titles {
+		n := 0
+		for _, r := range records {
+			if r.Title() == title {
+				n++
+			}
+		}
+		if n > 1 {
+			return "", nil, nil, fmt.Errorf("expected 1 record - found %d records with title: %s", n, title)
+		}
+	}
+
+	values := map[string]string{}
+	for _, reference := range references {
+		// FindNotation resolves the notation against the fetched records only
+		value, err := (&core.SecretsManager{}).FindNotation(records, reference)
+		if err != nil {
+			return "", nil, nil, notationError(reference, err)
+		}
+		result, err := newNotationResult(value)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("reference '%s': %w", reference, err)
+		}
+		values[reference] = result.Value
+	}
+
+	rendered := templateReferencePattern.ReplaceAllStringFunc(template, func(match string) string {
+		return values[templateReference(match)]
+	})
+	return rendered, references, fallback, nil
+}
+
+// templateReference returns the notation of a template reference - without
+// the ${} delimiters.
+func templateReference(match string) string {
+	if strings.HasPrefix(match, "${") {
+		return strings.TrimSuffix(strings.TrimPrefix(match, "${"), "}")
+	}
+	return match
+}
@@ -0,0 +1,119 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceTemplate(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	dbUid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":  "databaseCredentials",
+		"title": "db",
+		"fields": []interface{}{
+			map[string]interface{}{"type": "login", "value": []interface{}{"admin"}},
+			map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}},
+		},
+	})
+	vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "api",
+		"custom": []interface{}{map[string]interface{}{"type": "text", "label": "Token", "value": []interface{}{"t0ken"}}},
+	})
+
+	template := "db:\n  user: keeper://" + dbUid + "/field/login\n  password: \"keeper://" + dbUid + "/field/password\"\n" +
+		"  again: keeper://" + dbUid + "/field/password\napi_token: 'keeper://api/custom_field/Token'\n"
+	ds := Provider().DataSourcesMap["secretsmanager_template"]
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"template": template})
+	if diags := ds.ReadContext(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	want := "db:\n  user: admin\n  password: \"s3cr3t\"\n  again: s3cr3t\napi_token: 't0ken'\n"
+	if got := d.Get("rendered").(string); got != want {
+		t.Errorf("rendered = %q, want %q", got, want)
+	}
+	if references := d.Get("references").([]interface{}); len(references) != 3 {
+		t.Errorf("references = %v, want 3 distinct references", references)
+	}
+	if vault.calls["GetSecrets"] != 1 {
+		t.Errorf("GetSecrets called %d times, want 1", vault.calls["GetSecrets"])
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+	vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "dup"})
+	vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "dup"})
+
+	// without references nothing is fetched
+	rendered, references, _, err := renderTemplate(ctx, vault, "no references")
+	if err != nil || rendered != "no references" || len(references) != 0 || vault.calls["GetSecrets"] != 0 {
+		t.Errorf("template without references = %q, %v, %v", rendered, references, err)
+	}
+
+	_, _, _, err = renderTemplate(ctx, vault, "keeper://"+uid+"/field/password")
+	var matchErr *notationMatchError
+	if !errors.As(err, &matchErr) {
+		t.Errorf("missing field: got error %v, want notationMatchError", err)
+	}
+	if _, _, _, err = renderTemplate(ctx, vault, "keeper://dup/field/login"); err == nil || !strings.Contains(err.Error(), "found 2 records") {
+		t.Errorf("duplicate title: got error %v", err)
+	}
+	if _, _, _, err = renderTemplate(ctx, vault, "keeper://"+uid+"/field/login[x]"); err == nil {
+		t.Error("invalid reference: expected an error")
+	}
+}
+
+func TestRenderTemplateDelimitedReferences(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+	vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "databaseCredentials",
+		"title":  "My Database",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}}},
+	})
+
+	template := "user=${keeper://" + uid + "/field/login} password=${keeper://My Database/field/password}\n" +
+		"again=\"${keeper://My Database/field/password}\" bare=keeper://" + uid + "/field/login\n"
+	rendered, references, _, err := renderTemplate(ctx, vault, template)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if want := "user=admin password=s3cr3t\nagain=\"s3cr3t\" bare=admin\n"; rendered != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+	want := []string{"keeper://" + uid + "/field/login", "keeper://My Database/field/password"}
+	if !reflect.DeepEqual(references, want) {
+		t.Errorf("references = %v, want %v", references, want)
+	}
+
+	// references that don't resolve fail instead of staying in the rendered text
+	for name, template := range map[string]string{
+		"bare title with whitespace": "password=keeper://My Database/field/password",
+		"unterminated":               "password=${keeper://My Database/field/password",
+		"empty":                      "password=${keeper://}",
+		"missing record":             "password=${keeper://No Such Record/field/password}",
+		"missing field":              "password=${keeper://My Database/field/login}",
+	} {
+		if rendered, _, _, err := renderTemplate(ctx, vault, template); err == nil {
+			t.Errorf("%s: expected an error, rendered %q", name, rendered)
+		}
+	}
+}
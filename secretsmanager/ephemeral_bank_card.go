@@ -101,18 +101,19 @@ func (e *ephemeralBankCard) Open(ctx context.Context, req ephemeral.OpenRequest,
 		return
 	}
 
-	client := *e.meta.client
+	client := e.meta.client
 	path := strings.TrimSpace(data.Path.ValueString())
 	title := ""
 	if !data.Title.IsNull() && !data.Title.IsUnknown() {
 		title = strings.TrimSpace(data.Title.ValueString())
 	}
 
-	secret, err := getRecord(path, title, client)
+	secret, fallback, err := readRecord(ctx, path, title, client)
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading secret", err.Error())
 		return
 	}
+	fallback.addWarning(&resp.Diagnostics)
 
 	recordType := secret.Type()
 	if recordType != "bankCard" {
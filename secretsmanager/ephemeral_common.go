@@ -145,34 +145,41 @@ func fileItemsToListValue(ctx context.Context, files []*core.KeeperFile) (types.
 }
 
 // hostToListValue converts KSM host field data to a Framework types.List.
+// host is multi-value so every entry is returned in record order.
 func hostToListValue(ctx context.Context, secret *core.Record) (types.List, diag.Diagnostics) {
 	fields := secret.GetFieldsByType("host")
 	if len(fields) == 0 {
 		return types.ListValueMust(hostObjectType, []attr.Value{}), nil
 	}
 
-	hostName := ""
-	port := ""
-	if values, ok := fields[0]["value"].([]interface{}); ok && len(values) > 0 {
-		if vmap, ok := values[0].(map[string]interface{}); ok {
+	items := []attr.Value{}
+	if values, ok := fields[0]["value"].([]interface{}); ok {
+		for _, value := range values {
+			vmap, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			hostName := ""
+			port := ""
 			if val, ok := vmap["hostName"].(string); ok {
 				hostName = val
 			}
 			if val, ok := vmap["port"].(string); ok {
 				port = val
 			}
-		}
-	}
 
-	obj, diags := types.ObjectValue(hostObjectType.AttrTypes, map[string]attr.Value{
-		"host_name": types.StringValue(hostName),
-		"port":      types.StringValue(port),
-	})
-	if diags.HasError() {
-		return types.ListNull(hostObjectType), diags
+			obj, diags := types.ObjectValue(hostObjectType.AttrTypes, map[string]attr.Value{
+				"host_name": types.StringValue(hostName),
+				"port":      types.StringValue(port),
+			})
+			if diags.HasError() {
+				return types.ListNull(hostObjectType), diags
+			}
+			items = append(items, obj)
+		}
 	}
 
-	return types.ListValue(hostObjectType, []attr.Value{obj})
+	return types.ListValue(hostObjectType, items)
 }
 
 // keyPairToListValue converts KSM key pair field data to a Framework types.List.
@@ -601,15 +608,21 @@ func addressToListValue(ctx context.Context, secret *core.Record) (types.List, d
 }
 
 // phoneToListValue converts KSM phone field data to a Framework types.List.
+// phone is multi-value so every entry is returned in record order.
 func phoneToListValue(ctx context.Context, secret *core.Record) (types.List, diag.Diagnostics) {
 	fields := secret.GetFieldsByType("phone")
 	if len(fields) == 0 {
 		return types.ListValueMust(phoneObjectType, []attr.Value{}), nil
 	}
 
-	region, number, ext, phoneType := "", "", "", ""
-	if values, ok := fields[0]["value"].([]interface{}); ok && len(values) > 0 {
-		if vmap, ok := values[0].(map[string]interface{}); ok {
+	items := []attr.Value{}
+	if values, ok := fields[0]["value"].([]interface{}); ok {
+		for _, value := range values {
+			vmap, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			region, number, ext, phoneType := "", "", "", ""
 			if val, ok := vmap["region"].(string); ok {
 				region = val
 			}
@@ -622,20 +635,21 @@ func phoneToListValue(ctx context.Context, secret *core.Record) (types.List, dia
 			if val, ok := vmap["type"].(string); ok {
 				phoneType = val
 			}
-		}
-	}
 
-	obj, diags := types.ObjectValue(phoneObjectType.AttrTypes, map[string]attr.Value{
-		"region": types.StringValue(region),
-		"number": types.StringValue(number),
-		"ext":    types.StringValue(ext),
-		"type":   types.StringValue(phoneType),
-	})
-	if diags.HasError() {
-		return types.ListNull(phoneObjectType), diags
+			obj, diags := types.ObjectValue(phoneObjectType.AttrTypes, map[string]attr.Value{
+				"region": types.StringValue(region),
+				"number": types.StringValue(number),
+				"ext":    types.StringValue(ext),
+				"type":   types.StringValue(phoneType),
+			})
+			if diags.HasError() {
+				return types.ListNull(phoneObjectType), diags
+			}
+			items = append(items, obj)
+		}
 	}
 
-	return types.ListValue(phoneObjectType, []attr.Value{obj})
+	return types.ListValue(phoneObjectType, items)
 }
 
 // paymentCardToListValue converts KSM paymentCard field data to a Framework types.List.
@@ -713,7 +727,7 @@ func bankAccountToListValue(ctx context.Context, secret *core.Record) (types.Lis
 // addressRefToListValue fetches the referenced address record and converts it to a Framework types.List.
 // If the UID is empty, returns an empty list. If the referenced record cannot be fetched, returns a
 // partial object (uid only, fields empty) with a warning diagnostic so callers are aware.
-func addressRefToListValue(ctx context.Context, secret *core.Record, client core.SecretsManager) (types.List, diag.Diagnostics) {
+func addressRefToListValue(ctx context.Context, secret *core.Record, client ksmClient) (types.List, diag.Diagnostics) {
 	uid := strings.TrimSpace(secret.GetFieldValueByType("addressRef"))
 	if uid == "" {
 		return types.ListValueMust(addressRefObjectType, []attr.Value{}), nil
@@ -730,7 +744,7 @@ func addressRefToListValue(ctx context.Context, secret *core.Record, client core
 	}
 
 	var diags diag.Diagnostics
-	refs, err := getSecrets(client, []string{uid})
+	refs, _, err := readSecrets(ctx, client, []string{uid})
 	if err != nil || len(refs) == 0 {
 		diags.AddWarning("Referenced Address Record Not Found",
 			"Could not fetch addressRef record with UID '"+uid+"'. Address fields will be empty.")
@@ -775,7 +789,7 @@ func addressRefToListValue(ctx context.Context, secret *core.Record, client core
 // cardRefToListValue fetches the referenced card record and converts it to a Framework types.List.
 // If the UID is empty, returns an empty list. If the referenced record cannot be fetched, returns a
 // partial object (uid only, fields empty) with a warning diagnostic so callers are aware.
-func cardRefToListValue(ctx context.Context, secret *core.Record, client core.SecretsManager) (types.List, diag.Diagnostics) {
+func cardRefToListValue(ctx context.Context, secret *core.Record, client ksmClient) (types.List, diag.Diagnostics) {
 	uid := strings.TrimSpace(secret.GetFieldValueByType("cardRef"))
 	if uid == "" {
 		return types.ListValueMust(cardRefObjectType, []attr.Value{}), nil
@@ -795,7 +809,7 @@ func cardRefToListValue(ctx context.Context, secret *core.Record, client core.Se
 	}
 
 	var diags diag.Diagnostics
-	refs, err := getSecrets(client, []string{uid})
+	refs, _, err := readSecrets(ctx, client, []string{uid})
 	if err != nil || len(refs) == 0 {
 		diags.AddWarning("Referenced Card Record Not Found",
 			"Could not fetch cardRef record with UID '"+uid+"'. Card fields will be empty.")
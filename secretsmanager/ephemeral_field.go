@@ -20,9 +20,11 @@ type ephemeralField struct {
 }
 
 type ephemeralFieldModel struct {
-	Path  types.String `tfsdk:"path"`
-	Title types.String `tfsdk:"title"`
-	Value types.String `tfsdk:"value"`
+	Path      types.String `tfsdk:"path"`
+	Title     types.String `tfsdk:"title"`
+	Value     types.String `tfsdk:"value"`
+	Values    types.List   `tfsdk:"values"`
+	ValueJson types.String `tfsdk:"value_json"`
 }
 
 func NewEphemeralField() ephemeral.EphemeralResource {
@@ -48,7 +50,18 @@ func (e *ephemeralField) Schema(_ context.Context, _ ephemeral.SchemaRequest, re
 			"value": schema.StringAttribute{
 				Computed:    true,
 				Sensitive:   true,
-				Description: "The value of the secret field.",
+				Description: fieldValueDescription,
+			},
+			"values": schema.ListAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: fieldValuesDescription,
+			},
+			"value_json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: fieldValueJsonDescription,
 			},
 		},
 	}
@@ -78,7 +91,7 @@ func (e *ephemeralField) Open(ctx context.Context, req ephemeral.OpenRequest, re
 		return
 	}
 
-	client := *e.meta.client
+	client := e.meta.client
 	path := strings.TrimSpace(data.Path.ValueString())
 	title := ""
 	if !data.Title.IsNull() && !data.Title.IsUnknown() {
@@ -87,11 +100,12 @@ func (e *ephemeralField) Open(ctx context.Context, req ephemeral.OpenRequest, re
 
 	// find by title requested
 	if title != "" && strings.Contains(path, "*") {
-		records, err := getSecrets(client, []string{})
+		records, fallback, err := readSecrets(ctx, client, []string{})
 		if err != nil {
 			resp.Diagnostics.AddError("Error fetching records", err.Error())
 			return
 		}
+		fallback.addWarning(&resp.Diagnostics)
 		uids := []string{}
 		for _, r := range records {
 			if r.Title() == title {
@@ -106,20 +120,29 @@ func (e *ephemeralField) Open(ctx context.Context, req ephemeral.OpenRequest, re
 		path = strings.Replace(path, "*", uids[0], 1)
 	}
 
-	value, err := getNotation(client, path)
+	value, fallback, err := readNotation(ctx, client, path)
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading field", err.Error())
 		return
 	}
+	if resp.Diagnostics.WarningsCount() == 0 {
+		fallback.addWarning(&resp.Diagnostics)
+	}
 
-	strValue := ""
-	if len(value) == 1 {
-		strValue = fmt.Sprintf("%v", value[0])
-	} else {
-		strValue = fmt.Sprintf("%v", value)
+	result, err := newNotationResult(value)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading field", err.Error())
+		return
+	}
+	values, diags := types.ListValueFrom(ctx, types.StringType, result.Values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	data.Value = types.StringValue(strValue)
+	data.Value = types.StringValue(result.Value)
+	data.Values = values
+	data.ValueJson = types.StringValue(result.ValueJson)
 
 	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
 }
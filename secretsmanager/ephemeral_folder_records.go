@@ -0,0 +1,146 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &ephemeralFolderRecords{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ephemeralFolderRecords{}
+)
+
+// ephemeralFolderRecords reads every record of a folder without writing the
+// values to state.
+type ephemeralFolderRecords struct {
+	meta providerMeta
+}
+
+type ephemeralFolderRecordsModel struct {
+	FolderUid         types.String `tfsdk:"folder_uid"`
+	IncludeSubfolders types.Bool   `tfsdk:"include_subfolders"`
+	Records           types.List   `tfsdk:"records"`
+	RecordsByUid      types.Map    `tfsdk:"records_by_uid"`
+}
+
+func NewEphemeralFolderRecords() ephemeral.EphemeralResource {
+	return &ephemeralFolderRecords{}
+}
+
+func (e *ephemeralFolderRecords) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_folder_records"
+}
+
+func (e *ephemeralFolderRecords) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this ephemeral resource to read all records of a folder. Values are never stored in state.",
+		Attributes: map[string]schema.Attribute{
+			"folder_uid": schema.StringAttribute{
+				Required:    true,
+				Description: "The UID of the shared folder or subfolder.",
+			},
+			"include_subfolders": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Also read the records of all subfolders of the folder. Default: false",
+			},
+			"records":        recordsEphemeralAttribute(),
+			"records_by_uid": recordsByUidEphemeralAttribute(),
+		},
+	}
+}
+
+func (e *ephemeralFolderRecords) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", "Expected providerMeta")
+		return
+	}
+	e.meta = meta
+}
+
+func (e *ephemeralFolderRecords) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralFolderRecordsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.meta.client == nil {
+		resp.Diagnostics.AddError("Provider Not Configured", "KSM client is not configured. Ensure the provider credential is set.")
+		return
+	}
+
+	client := e.meta.client
+	folderUid := strings.TrimSpace(data.FolderUid.ValueString())
+	folderUids := map[string]bool{folderUid: true}
+	if data.IncludeSubfolders.ValueBool() {
+		folders, err := getFolders(ctx, client)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading folders", err.Error())
+			return
+		}
+		if folderUids, err = folderTreeUids(folderUid, folders); err != nil {
+			resp.Diagnostics.AddError("Error reading folders", err.Error())
+			return
+		}
+	}
+
+	allSecrets, fallback, err := readSecrets(ctx, client, []string{})
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading records", fmt.Sprintf("failed to fetch all records: %v", err))
+		return
+	}
+	fallback.addWarning(&resp.Diagnostics)
+
+	secrets := []*core.Record{}
+	for _, secret := range allSecrets {
+		if folderUids[recordFolderUid(secret)] {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	var diags diag.Diagnostics
+	data.Records, data.RecordsByUid, diags = recordsToListValue(ctx, secrets)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// folderTreeUids returns the UIDs of the folder and all its subfolders.
+func folderTreeUids(folderUid string, folders []*core.KeeperFolder) (map[string]bool, error) {
+	children := map[string][]string{}
+	found := false
+	for _, folder := range folders {
+		children[folder.ParentUid] = append(children[folder.ParentUid], folder.FolderUid)
+		found = found || folder.FolderUid == folderUid
+	}
+	if !found {
+		return nil, fmt.Errorf("folder not found - UID: %s", folderUid)
+	}
+
+	uids := map[string]bool{}
+	pending := []string{folderUid}
+	for len(pending) > 0 {
+		uid := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if uids[uid] {
+			continue
+		}
+		uids[uid] = true
+		pending = append(pending, children[uid]...)
+	}
+	return uids, nil
+}
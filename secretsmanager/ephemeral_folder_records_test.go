@@ -0,0 +1,62 @@
+package secretsmanager
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEphemeralFolderRecordsOpen(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	subfolderUid := vault.AddFolder(folderUid, "sub")
+	nestedUid := vault.AddFolder(subfolderUid, "nested")
+	otherUid := vault.AddFolder("", "other")
+	sharedRecord := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "shared"})
+	subRecord := vault.AddRecord(subfolderUid, map[string]interface{}{"type": "login", "title": "sub"})
+	nestedRecord := vault.AddRecord(nestedUid, map[string]interface{}{"type": "login", "title": "nested"})
+	vault.AddRecord(otherUid, map[string]interface{}{"type": "login", "title": "other"})
+	e := &ephemeralFolderRecords{meta: providerMeta{client: vault}}
+
+	recordUids := func(attributes map[string]interface{}) []string {
+		t.Helper()
+		resp := openTestEphemeral(t, e, attributes)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("open: %v", resp.Diagnostics)
+		}
+		var data ephemeralFolderRecordsModel
+		resp.Diagnostics.Append(resp.Result.Get(ctx, &data)...)
+		byUid := map[string]string{}
+		resp.Diagnostics.Append(data.RecordsByUid.ElementsAs(ctx, &byUid, false)...)
+		if len(data.Records.Elements()) != len(byUid) {
+			t.Fatalf("%d records, %d records_by_uid", len(data.Records.Elements()), len(byUid))
+		}
+		uids := []string{}
+		for uid := range byUid {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+		return uids
+	}
+	sorted := func(uids ...string) []string {
+		sort.Strings(uids)
+		return uids
+	}
+
+	if uids := recordUids(map[string]interface{}{"folder_uid": folderUid}); !reflect.DeepEqual(uids, sorted(sharedRecord)) {
+		t.Errorf("shared folder records = %v", uids)
+	}
+	if uids := recordUids(map[string]interface{}{"folder_uid": subfolderUid}); !reflect.DeepEqual(uids, sorted(subRecord)) {
+		t.Errorf("subfolder records = %v", uids)
+	}
+	uids := recordUids(map[string]interface{}{"folder_uid": folderUid, "include_subfolders": true})
+	if !reflect.DeepEqual(uids, sorted(sharedRecord, subRecord, nestedRecord)) {
+		t.Errorf("folder tree records = %v", uids)
+	}
+
+	resp := openTestEphemeral(t, e, map[string]interface{}{"folder_uid": "missing", "include_subfolders": true})
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a missing folder")
+	}
+}
@@ -0,0 +1,180 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &ephemeralPassword{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ephemeralPassword{}
+)
+
+// ephemeralPassword generates a password on every open - nothing is sent to
+// Keeper or stored. The provider is only needed for its password policy.
+type ephemeralPassword struct {
+	meta providerMeta
+}
+
+type ephemeralPasswordModel struct {
+	Length              types.Int64               `tfsdk:"length"`
+	Caps                types.Int64               `tfsdk:"caps"`
+	Lowercase           types.Int64               `tfsdk:"lowercase"`
+	Digits              types.Int64               `tfsdk:"digits"`
+	Special             types.Int64               `tfsdk:"special"`
+	SpecialCharacters   types.String              `tfsdk:"special_characters"`
+	ExcludeCharacters   types.String              `tfsdk:"exclude_characters"`
+	ExcludeAmbiguous    types.Bool                `tfsdk:"exclude_ambiguous"`
+	MustStartWithLetter types.Bool                `tfsdk:"must_start_with_letter"`
+	Passphrase          *ephemeralPassphraseModel `tfsdk:"passphrase"`
+	Value               types.String              `tfsdk:"value"`
+}
+
+type ephemeralPassphraseModel struct {
+	Words      types.Int64  `tfsdk:"words"`
+	Separator  types.String `tfsdk:"separator"`
+	Capitalize types.Bool   `tfsdk:"capitalize"`
+}
+
+func NewEphemeralPassword() ephemeral.EphemeralResource {
+	return &ephemeralPassword{}
+}
+
+func (e *ephemeralPassword) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_password"
+}
+
+func (e *ephemeralPassword) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this ephemeral resource to generate a password or passphrase with the same complexity options as generated password fields. " +
+			"The password is never stored in state - pass it to write-only attributes or save it with a record resource.",
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Password length. Defaults to 16.",
+			},
+			"caps": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of uppercase characters - a minimum when positive, an exact count when 0 or negative.",
+			},
+			"lowercase": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of lowercase characters - a minimum when positive, an exact count when 0 or negative.",
+			},
+			"digits": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of digits - a minimum when positive, an exact count when 0 or negative.",
+			},
+			"special": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of special characters - a minimum when positive, an exact count when 0 or negative.",
+			},
+			"special_characters": schema.StringAttribute{
+				Optional:    true,
+				Description: "Set of special characters to pick from, instead of the default ASCII punctuation.",
+			},
+			"exclude_characters": schema.StringAttribute{
+				Optional:    true,
+				Description: "Characters never to use - excluded letters and digits are replaced with others of the same class.",
+			},
+			"exclude_ambiguous": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Leave out characters that are easily mistaken for each other (`0Oo1lI|`).",
+			},
+			"must_start_with_letter": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Start the password with a letter.",
+			},
+			"passphrase": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Generate words from an embedded wordlist instead of characters - the other options are ignored.",
+				Attributes: map[string]schema.Attribute{
+					"words": schema.Int64Attribute{
+						Optional:    true,
+						Description: fmt.Sprintf("Number of words, at least %d. Defaults to %d.", minPassphraseWords, defaultPassphraseWords),
+					},
+					"separator": schema.StringAttribute{
+						Optional:    true,
+						Description: fmt.Sprintf("Separator between the words. Defaults to `%s`.", defaultPassphraseSeparator),
+					},
+					"capitalize": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Capitalize the first letter of every word.",
+					},
+				},
+			},
+			"value": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated password.",
+			},
+		},
+	}
+}
+
+func (e *ephemeralPassword) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", "Expected providerMeta")
+		return
+	}
+	e.meta = meta
+}
+
+func (e *ephemeralPassword) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralPasswordModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	options := passwordOptions{
+		Length:              int(data.Length.ValueInt64()),
+		Caps:                passwordCountValue(data.Caps),
+		Lowercase:           passwordCountValue(data.Lowercase),
+		Digits:              passwordCountValue(data.Digits),
+		Special:             passwordCountValue(data.Special),
+		SpecialCharacters:   data.SpecialCharacters.ValueString(),
+		ExcludeCharacters:   data.ExcludeCharacters.ValueString(),
+		ExcludeAmbiguous:    data.ExcludeAmbiguous.ValueBool(),
+		MustStartWithLetter: data.MustStartWithLetter.ValueBool(),
+	}
+	if data.Passphrase != nil {
+		options.Passphrase = &passphraseOptions{
+			Words:      int(data.Passphrase.Words.ValueInt64()),
+			Separator:  defaultPassphraseSeparator,
+			Capitalize: data.Passphrase.Capitalize.ValueBool(),
+		}
+		if !data.Passphrase.Separator.IsNull() {
+			options.Passphrase.Separator = data.Passphrase.Separator.ValueString()
+		}
+	}
+	if err := e.meta.passwordPolicy.checkOptions("secretsmanager_password", options); err != nil {
+		resp.Diagnostics.AddError("Error generating password", err.Error())
+		return
+	}
+	password, err := generatePassword(e.meta.passwordPolicy.applyDefaults(options))
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating password", err.Error())
+		return
+	}
+
+	data.Value = types.StringValue(password)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// passwordCountValue is the count of a character class, nil when not set.
+func passwordCountValue(v types.Int64) *int {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	n := int(v.ValueInt64())
+	return &n
+}
@@ -0,0 +1,152 @@
+package secretsmanager
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// openTestEphemeral opens the ephemeral resource with the config attributes -
+// the others are null.
+func openTestEphemeral(t *testing.T, e ephemeral.EphemeralResource, attributes map[string]interface{}) *ephemeral.OpenResponse {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &ephemeral.SchemaResponse{}
+	e.Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	config := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		config[name] = tftypes.NewValue(attrType, attributes[name])
+	}
+	req := ephemeral.OpenRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, config)}}
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	e.Open(ctx, req, resp)
+	return resp
+}
+
+func openTestPassword(t *testing.T, attributes map[string]interface{}) (string, *ephemeral.OpenResponse) {
+	t.Helper()
+	resp := openTestEphemeral(t, NewEphemeralPassword(), attributes)
+	if resp.Diagnostics.HasError() {
+		return "", resp
+	}
+	var data ephemeralPasswordModel
+	if diags := resp.Result.Get(context.Background(), &data); diags.HasError() {
+		t.Fatalf("result: %v", diags)
+	}
+	return data.Value.ValueString(), resp
+}
+
+func countRunes(s string, match func(rune) bool) int {
+	n := 0
+	for _, r := range s {
+		if match(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEphemeralPasswordOpen(t *testing.T) {
+	password, resp := openTestPassword(t, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	if len(password) != defaultPasswordLength {
+		t.Errorf("expected the default length %d, got %q", defaultPasswordLength, password)
+	}
+
+	password, resp = openTestPassword(t, map[string]interface{}{
+		"length":             24,
+		"caps":               5,
+		"digits":             4,
+		"special":            0,
+		"special_characters": "#",
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	if len(password) != 24 || countRunes(password, unicode.IsUpper) < 5 || countRunes(password, unicode.IsDigit) < 4 || strings.Contains(password, "#") {
+		t.Errorf("password %q doesn't match the complexity", password)
+	}
+
+	password, resp = openTestPassword(t, map[string]interface{}{"length": 64, "special": 16, "special_characters": "#|", "exclude_ambiguous": true})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	if strings.ContainsAny(password, ambiguousCharacters) || countRunes(password, func(r rune) bool { return r == '#' }) < 16 {
+		t.Errorf("password %q has ambiguous characters or lost special characters", password)
+	}
+
+	password, resp = openTestPassword(t, map[string]interface{}{
+		"passphrase": map[string]tftypes.Value{
+			"words":      tftypes.NewValue(tftypes.Number, 6),
+			"separator":  tftypes.NewValue(tftypes.String, " "),
+			"capitalize": tftypes.NewValue(tftypes.Bool, nil),
+		},
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	if words := strings.Split(password, " "); len(words) != 6 {
+		t.Errorf("passphrase %q doesn't have 6 words", password)
+	}
+
+	// same validation as generated password fields
+	if _, resp = openTestPassword(t, map[string]interface{}{"length": 4}); !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a password shorter than 8 characters")
+	}
+	if _, resp = openTestPassword(t, map[string]interface{}{"special_characters": "|", "exclude_ambiguous": true}); !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a special character set with only ambiguous characters")
+	}
+}
+
+func TestGeneratePasswordUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		password, err := generatePassword(passwordOptions{ExcludeAmbiguous: true})
+		if err != nil {
+			t.Fatalf("generatePassword: %v", err)
+		}
+		if seen[password] {
+			t.Fatalf("password %q generated twice", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestAccEphemeralPassword(t *testing.T) {
+	config := `
+		ephemeral "secretsmanager_password" "db" {
+			length            = 20
+			digits            = 2
+			exclude_ambiguous = true
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 testAccPreCheck(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: `
+					ephemeral "secretsmanager_password" "short" {
+						length = 4
+					}
+				`,
+				ExpectError: regexp.MustCompile(`Error generating password`),
+			},
+		},
+	})
+}
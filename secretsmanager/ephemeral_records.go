@@ -0,0 +1,222 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &ephemeralRecords{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ephemeralRecords{}
+)
+
+// ephemeralRecords is the secretsmanager_records data source without
+// writing the values to state.
+type ephemeralRecords struct {
+	meta providerMeta
+}
+
+type ephemeralRecordsModel struct {
+	Uids          types.List `tfsdk:"uids"`
+	Titles        types.List `tfsdk:"titles"`
+	TitlePatterns types.List `tfsdk:"title_patterns"`
+	Records       types.List `tfsdk:"records"`
+	RecordsByUid  types.Map  `tfsdk:"records_by_uid"`
+}
+
+func NewEphemeralRecords() ephemeral.EphemeralResource {
+	return &ephemeralRecords{}
+}
+
+func (e *ephemeralRecords) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_records"
+}
+
+func (e *ephemeralRecords) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this ephemeral resource to read multiple records by UID, title or title pattern. Values are never stored in state.",
+		Attributes: map[string]schema.Attribute{
+			"uids": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List of record UIDs to fetch. Most efficient option - fetches only requested records.",
+			},
+			"titles": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List of exact record titles to match. WARNING: Fetches ALL vault records and filters client-side. Use 'uids' for better performance.",
+			},
+			"title_patterns": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List of regex patterns (Go syntax) to match record titles. WARNING: Fetches ALL vault records and filters client-side. Use 'uids' for better performance. Max pattern length: 500 chars.",
+			},
+			"records":        recordsEphemeralAttribute(),
+			"records_by_uid": recordsByUidEphemeralAttribute(),
+		},
+	}
+}
+
+func (e *ephemeralRecords) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", "Expected providerMeta")
+		return
+	}
+	e.meta = meta
+}
+
+func (e *ephemeralRecords) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralRecordsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.meta.client == nil {
+		resp.Diagnostics.AddError("Provider Not Configured", "KSM client is not configured. Ensure the provider credential is set.")
+		return
+	}
+
+	var uids, titles, patterns []string
+	for _, list := range []struct {
+		value  types.List
+		target *[]string
+	}{
+		{data.Uids, &uids},
+		{data.Titles, &titles},
+		{data.TitlePatterns, &patterns},
+	} {
+		resp.Diagnostics.Append(list.value.ElementsAs(ctx, list.target, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets, fallback, err := findRecords(ctx, e.meta.client, uids, titles, patterns)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading records", err.Error())
+		return
+	}
+	fallback.addWarning(&resp.Diagnostics)
+
+	var diags diag.Diagnostics
+	data.Records, data.RecordsByUid, diags = recordsToListValue(ctx, secrets)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// recordsEphemeralAttribute returns the computed records list of the ephemeral
+// resources reading multiple records.
+func recordsEphemeralAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "List of fetched records.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"uid": schema.StringAttribute{
+					Computed:    true,
+					Description: "The record UID.",
+				},
+				"type": schema.StringAttribute{
+					Computed:    true,
+					Description: "The secret type.",
+				},
+				"title": schema.StringAttribute{
+					Computed:    true,
+					Description: "The secret title.",
+				},
+				"notes": schema.StringAttribute{
+					Computed:    true,
+					Description: "The secret notes.",
+				},
+				"fields":   genericFieldEphemeralAttribute("Standard fields of the record."),
+				"custom":   genericFieldEphemeralAttribute("Custom fields of the record."),
+				"file_ref": fileRefEphemeralAttribute(),
+			},
+		},
+	}
+}
+
+// recordsByUidEphemeralAttribute returns the computed map of JSON-encoded
+// records keyed by UID.
+func recordsByUidEphemeralAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		Computed:    true,
+		Sensitive:   true,
+		ElementType: types.StringType,
+		Description: "Map of records keyed by UID for direct access (JSON-encoded).",
+	}
+}
+
+var recordObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"uid":      types.StringType,
+		"type":     types.StringType,
+		"title":    types.StringType,
+		"notes":    types.StringType,
+		"fields":   types.ListType{ElemType: genericFieldObjectType},
+		"custom":   types.ListType{ElemType: genericFieldObjectType},
+		"file_ref": types.ListType{ElemType: fileRefObjectType},
+	},
+}
+
+// recordsToListValue converts the records to the Framework records list and
+// records_by_uid map.
+func recordsToListValue(ctx context.Context, secrets []*core.Record) (types.List, types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	objects := make([]attr.Value, 0, len(secrets))
+	byUid := map[string]attr.Value{}
+	for _, secret := range secrets {
+		// downloads the file content used by fileItemsToListValue too
+		record := getRecordItemData(secret, allFileContent)
+		if jsonData, err := json.Marshal(record); err == nil {
+			byUid[secret.Uid] = types.StringValue(string(jsonData))
+		}
+
+		fields, d := genericFieldItemsToListValue(ctx, record["fields"].([]interface{}))
+		diags.Append(d...)
+		custom, d := genericFieldItemsToListValue(ctx, record["custom"].([]interface{}))
+		diags.Append(d...)
+		fileRef, d := fileItemsToListValue(ctx, secret.Files)
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.ListNull(recordObjectType), types.MapNull(types.StringType), diags
+		}
+
+		obj, d := types.ObjectValue(recordObjectType.AttrTypes, map[string]attr.Value{
+			"uid":      types.StringValue(secret.Uid),
+			"type":     types.StringValue(secret.Type()),
+			"title":    types.StringValue(secret.Title()),
+			"notes":    types.StringValue(secret.Notes()),
+			"fields":   fields,
+			"custom":   custom,
+			"file_ref": fileRef,
+		})
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.ListNull(recordObjectType), types.MapNull(types.StringType), diags
+		}
+		objects = append(objects, obj)
+	}
+
+	list, d := types.ListValue(recordObjectType, objects)
+	diags.Append(d...)
+	recordsByUid, d := types.MapValue(types.StringType, byUid)
+	diags.Append(d...)
+	return list, recordsByUid, diags
+}
@@ -0,0 +1,68 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func stringValues(values ...string) []tftypes.Value {
+	items := make([]tftypes.Value, len(values))
+	for i, value := range values {
+		items[i] = tftypes.NewValue(tftypes.String, value)
+	}
+	return items
+}
+
+func TestEphemeralRecordsOpen(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	loginUid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}}},
+	})
+	vault.AddFile(loginUid, "cert.pem", []byte("pem"))
+	dbUid := vault.AddRecord(folderUid, map[string]interface{}{"type": "databaseCredentials", "title": "db-prod"})
+	vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "other"})
+	e := &ephemeralRecords{meta: providerMeta{client: vault}}
+
+	resp := openTestEphemeral(t, e, map[string]interface{}{
+		"uids":           stringValues(loginUid),
+		"title_patterns": stringValues(" ^db-"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	var data ephemeralRecordsModel
+	resp.Diagnostics.Append(resp.Result.Get(ctx, &data)...)
+	if n := len(data.Records.Elements()); n != 2 {
+		t.Fatalf("got %d records, want 2", n)
+	}
+	byUid := map[string]string{}
+	resp.Diagnostics.Append(data.RecordsByUid.ElementsAs(ctx, &byUid, false)...)
+	if _, ok := byUid[dbUid]; !ok || len(byUid) != 2 {
+		t.Fatalf("records_by_uid = %v", byUid)
+	}
+	var login map[string]interface{}
+	if err := json.Unmarshal([]byte(byUid[loginUid]), &login); err != nil {
+		t.Fatalf("records_by_uid JSON: %v", err)
+	}
+	if login["title"] != "web" || !strings.Contains(byUid[loginUid], "s3cr3t") || len(login["file_ref"].([]interface{})) != 1 {
+		t.Errorf("unexpected record %s", byUid[loginUid])
+	}
+
+	for name, attributes := range map[string]map[string]interface{}{
+		"no filter":     nil,
+		"missing uid":   {"uids": stringValues("missing")},
+		"missing title": {"titles": stringValues("missing")},
+		"bad pattern":   {"title_patterns": stringValues("(")},
+	} {
+		if resp := openTestEphemeral(t, e, attributes); !resp.Diagnostics.HasError() {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// tempFileDirKey is the private data key of the directory Open created -
+// Close removes it.
+const tempFileDirKey = "temp_dir"
+
+var (
+	_ ephemeral.EphemeralResource              = &ephemeralTempFile{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ephemeralTempFile{}
+	_ ephemeral.EphemeralResourceWithClose     = &ephemeralTempFile{}
+)
+
+// ephemeralTempFile writes the attachments of a record to a temporary
+// directory for the duration of the run - for provisioners and providers
+// that read files, such as keystores too large for content_base64.
+type ephemeralTempFile struct {
+	meta providerMeta
+}
+
+type ephemeralTempFileModel struct {
+	Path      types.String `tfsdk:"path"`
+	Title     types.String `tfsdk:"title"`
+	FileName  types.String `tfsdk:"file_name"`
+	Directory types.String `tfsdk:"directory"`
+	TempDir   types.String `tfsdk:"temp_dir"`
+	FilePath  types.String `tfsdk:"file_path"`
+	Files     types.List   `tfsdk:"files"`
+}
+
+var tempFileObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"uid":       types.StringType,
+		"title":     types.StringType,
+		"name":      types.StringType,
+		"type":      types.StringType,
+		"size":      types.Int64Type,
+		"file_path": types.StringType,
+	},
+}
+
+func NewEphemeralTempFile() ephemeral.EphemeralResource {
+	return &ephemeralTempFile{}
+}
+
+func (e *ephemeralTempFile) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_temp_file"
+}
+
+func (e *ephemeralTempFile) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this ephemeral resource to write the file attachments of a record to a temporary directory. " +
+			"The files are readable by the owner only (0600) and deleted when Terraform closes the ephemeral resource.",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "The path where the secret is stored.",
+			},
+			"title": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The secret title.",
+			},
+			"file_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name or title of the attachment to write. All attachments of the record are written when not set.",
+			},
+			"directory": schema.StringAttribute{
+				Optional:    true,
+				Description: "The directory the temporary directory is created in. Default: the system temporary directory",
+			},
+			"temp_dir": schema.StringAttribute{
+				Computed:    true,
+				Description: "The temporary directory holding the files.",
+			},
+			"file_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The path of the written file - the first one when several attachments are written.",
+			},
+			"files": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The written files.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uid": schema.StringAttribute{
+							Computed:    true,
+							Description: "The file ref UID.",
+						},
+						"title": schema.StringAttribute{
+							Computed:    true,
+							Description: "The file title.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The file name.",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The file type.",
+						},
+						"size": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The file size.",
+						},
+						"file_path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The path of the written file.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (e *ephemeralTempFile) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", "Expected providerMeta")
+		return
+	}
+	e.meta = meta
+}
+
+func (e *ephemeralTempFile) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralTempFileModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.meta.client == nil {
+		resp.Diagnostics.AddError("Provider Not Configured", "KSM client is not configured. Ensure the provider credential is set.")
+		return
+	}
+
+	client := e.meta.client
+	path := strings.TrimSpace(data.Path.ValueString())
+	title := ""
+	if !data.Title.IsNull() && !data.Title.IsUnknown() {
+		title = strings.TrimSpace(data.Title.ValueString())
+	}
+
+	secret, fallback, err := readRecord(ctx, path, title, client)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading secret", err.Error())
+		return
+	}
+	fallback.addWarning(&resp.Diagnostics)
+
+	files, err := selectFiles(secret.Files, strings.TrimSpace(data.FileName.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading secret", fmt.Sprintf("record UID %s: %v", secret.Uid, err))
+		return
+	}
+
+	tempDir, paths, err := writeTempFiles(data.Directory.ValueString(), files)
+	if err != nil {
+		resp.Diagnostics.AddError("Error writing files", err.Error())
+		return
+	}
+	// the directory is removed by Close - or now when Open fails
+	cleanup := true
+	defer func() {
+		if cleanup {
+			_ = os.RemoveAll(tempDir)
+		}
+	}()
+
+	dirJson, err := json.Marshal(tempDir)
+	if err != nil {
+		resp.Diagnostics.AddError("Error writing files", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, tempFileDirKey, dirJson)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	items := make([]attr.Value, 0, len(files))
+	for i, f := range files {
+		obj, diags := types.ObjectValue(tempFileObjectType.AttrTypes, map[string]attr.Value{
+			"uid":       types.StringValue(f.Uid),
+			"title":     types.StringValue(f.Title),
+			"name":      types.StringValue(f.Name),
+			"type":      types.StringValue(f.Type),
+			"size":      types.Int64Value(int64(f.Size)),
+			"file_path": types.StringValue(paths[i]),
+		})
+		resp.Diagnostics.Append(diags...)
+		items = append(items, obj)
+	}
+	filesList, diags := types.ListValue(tempFileObjectType, items)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Title = types.StringValue(secret.Title())
+	data.TempDir = types.StringValue(tempDir)
+	data.FilePath = types.StringValue(paths[0])
+	data.Files = filesList
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	cleanup = resp.Diagnostics.HasError()
+}
+
+func (e *ephemeralTempFile) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	dirJson, diags := req.Private.GetKey(ctx, tempFileDirKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || dirJson == nil {
+		return
+	}
+	var tempDir string
+	if err := json.Unmarshal(dirJson, &tempDir); err != nil {
+		resp.Diagnostics.AddError("Error removing files", err.Error())
+		return
+	}
+	if err := os.RemoveAll(tempDir); err != nil {
+		resp.Diagnostics.AddError("Error removing files", err.Error())
+	}
+}
+
+// selectFiles returns the attachment with the name or title, all of them when
+// name is empty - an error when there are none.
+func selectFiles(files []*core.KeeperFile, name string) ([]*core.KeeperFile, error) {
+	if name == "" {
+		if len(files) == 0 {
+			return nil, fmt.Errorf("the record has no file attachments")
+		}
+		return files, nil
+	}
+	for _, f := range files {
+		if f.Name == name || f.Title == name {
+			return []*core.KeeperFile{f}, nil
+		}
+	}
+	return nil, fmt.Errorf("file attachment not found - name: %s", name)
+}
+
+// writeTempFiles creates a temporary directory in directory (the system
+// temporary directory when empty) and writes the files to it with mode 0600.
+// It returns the directory and the path of each file.
+func writeTempFiles(directory string, files []*core.KeeperFile) (string, []string, error) {
+	tempDir, err := os.MkdirTemp(directory, "secretsmanager-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	paths := make([]string, 0, len(files))
+	used := map[string]bool{}
+	for _, f := range files {
+		data := f.GetFileData()
+		if len(data) == 0 && f.Size > 0 {
+			_ = os.RemoveAll(tempDir)
+			return "", nil, fmt.Errorf("failed to download file UID %s", f.Uid)
+		}
+		// file names come from the vault - never leave the directory
+		name := filepath.Base(filepath.Clean("/" + f.Name))
+		if name == string(filepath.Separator) {
+			name = f.Uid
+		}
+		if used[name] {
+			name = f.Uid + "_" + name
+		}
+		used[name] = true
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			_ = os.RemoveAll(tempDir)
+			return "", nil, fmt.Errorf("failed to write file UID %s: %w", f.Uid, err)
+		}
+		paths = append(paths, path)
+	}
+	return tempDir, paths, nil
+}
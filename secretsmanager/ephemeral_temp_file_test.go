@@ -0,0 +1,105 @@
+package secretsmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func TestWriteTempFiles(t *testing.T) {
+	directory := t.TempDir()
+	files := []*core.KeeperFile{
+		{Uid: "uid1", Name: "keystore.jks", Size: 3, FileData: []byte("jks")},
+		{Uid: "uid2", Name: "keystore.jks", Size: 3, FileData: []byte("two")},
+		{Uid: "uid3", Name: "../../escape", Size: 6, FileData: []byte("escape")},
+	}
+	tempDir, paths, err := writeTempFiles(directory, files)
+	if err != nil {
+		t.Fatalf("writeTempFiles: %v", err)
+	}
+	if filepath.Dir(tempDir) != directory {
+		t.Errorf("temporary directory %s isn't in %s", tempDir, directory)
+	}
+	want := []string{"keystore.jks", "uid2_keystore.jks", "escape"}
+	for i, path := range paths {
+		if path != filepath.Join(tempDir, want[i]) {
+			t.Errorf("path = %s, want %s", path, filepath.Join(tempDir, want[i]))
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || string(content) != string(files[i].FileData) {
+			t.Errorf("%s = %q, %v", path, content, err)
+		}
+		info, err := os.Stat(path)
+		if err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode = %v, want 0600", path, info.Mode().Perm())
+		}
+	}
+
+	if _, err := selectFiles(files, "missing"); err == nil {
+		t.Error("expected an error for a missing attachment")
+	}
+	if _, err := selectFiles(nil, ""); err == nil {
+		t.Error("expected an error for a record without attachments")
+	}
+}
+
+// TestEphemeralTempFileClose checks the files are removed when Terraform
+// closes the resource - through the provider server so the logging wrapper
+// has to forward Close.
+func TestEphemeralTempFileClose(t *testing.T) {
+	ctx := context.Background()
+	s := newKsmTestServer(t)
+	clientFactory := newKsmClient
+	newKsmClient = newSecretsManagerClient
+	t.Cleanup(func() { newKsmClient = clientFactory })
+
+	folderUid := s.AddFolder("", "shared")
+	uid := s.AddRecord(folderUid, map[string]interface{}{"type": "file", "title": "keystore"})
+	s.AddFile(uid, "app.jks", []byte("keystore"))
+	s.AddFile(uid, "other.txt", []byte("other"))
+
+	server, schemas := newTestProviderServer(t, s)
+	ephemeralType := schemas.EphemeralResourceSchemas["secretsmanager_temp_file"].ValueType().(tftypes.Object)
+	opened, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "secretsmanager_temp_file",
+		Config: testDynamicValue(t, testObjectValue(ephemeralType, map[string]tftypes.Value{
+			"path":      tftypes.NewValue(tftypes.String, uid),
+			"file_name": tftypes.NewValue(tftypes.String, "app.jks"),
+			"directory": tftypes.NewValue(tftypes.String, t.TempDir()),
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(opened.Diagnostics); err != nil {
+		t.Fatalf("open ephemeral resource: %v", err)
+	}
+	path := testStringAttribute(t, opened.Result, ephemeralType, "file_path")
+	if content, err := os.ReadFile(path); err != nil || string(content) != "keystore" {
+		t.Fatalf("%s = %q, %v", path, content, err)
+	}
+	tempDir := testStringAttribute(t, opened.Result, ephemeralType, "temp_dir")
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 1 {
+		t.Errorf("%d files written, want 1", len(entries))
+	}
+
+	closed, err := server.CloseEphemeralResource(ctx, &tfprotov6.CloseEphemeralResourceRequest{
+		TypeName: "secretsmanager_temp_file",
+		Private:  opened.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(closed.Diagnostics); err != nil {
+		t.Fatalf("close ephemeral resource: %v", err)
+	}
+	if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after close: %v", tempDir, err)
+	}
+}
@@ -0,0 +1,100 @@
+package secretsmanager
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &ephemeralTemplate{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ephemeralTemplate{}
+)
+
+// ephemeralTemplate is the secretsmanager_template data source without
+// writing the rendered text to state.
+type ephemeralTemplate struct {
+	meta providerMeta
+}
+
+type ephemeralTemplateModel struct {
+	Template   types.String `tfsdk:"template"`
+	Rendered   types.String `tfsdk:"rendered"`
+	References types.List   `tfsdk:"references"`
+}
+
+func NewEphemeralTemplate() ephemeral.EphemeralResource {
+	return &ephemeralTemplate{}
+}
+
+func (e *ephemeralTemplate) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template"
+}
+
+func (e *ephemeralTemplate) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this ephemeral resource to replace the `keeper://` references of a text template with the values they select. " +
+			"Values are never stored in state.",
+		Attributes: map[string]schema.Attribute{
+			"template": schema.StringAttribute{
+				Required:    true,
+				Description: templateDescription,
+			},
+			"rendered": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: templateRenderedDescription,
+			},
+			"references": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: templateReferencesDescription,
+			},
+		},
+	}
+}
+
+func (e *ephemeralTemplate) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", "Expected providerMeta")
+		return
+	}
+	e.meta = meta
+}
+
+func (e *ephemeralTemplate) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralTemplateModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.meta.client == nil {
+		resp.Diagnostics.AddError("Provider Not Configured", "KSM client is not configured. Ensure the provider credential is set.")
+		return
+	}
+
+	rendered, references, fallback, err := renderTemplate(ctx, e.meta.client, data.Template.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error rendering template", err.Error())
+		return
+	}
+	fallback.addWarning(&resp.Diagnostics)
+
+	referenceList, diags := types.ListValueFrom(ctx, types.StringType, references)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Rendered = types.StringValue(rendered)
+	data.References = referenceList
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,40 @@
+package secretsmanager
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEphemeralTemplateOpen(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}}},
+	})
+	e := &ephemeralTemplate{meta: providerMeta{client: vault}}
+
+	resp := openTestEphemeral(t, e, map[string]interface{}{"template": "PASSWORD=keeper://" + uid + "/field/password\n"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	var data ephemeralTemplateModel
+	resp.Diagnostics.Append(resp.Result.Get(ctx, &data)...)
+	if got := data.Rendered.ValueString(); got != "PASSWORD=s3cr3t\n" {
+		t.Errorf("rendered = %q", got)
+	}
+	if n := len(data.References.Elements()); n != 1 {
+		t.Errorf("got %d references, want 1", n)
+	}
+
+	resp = openTestEphemeral(t, e, map[string]interface{}{"template": "keeper://" + uid + "/field/login"})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a reference that doesn't match")
+	}
+
+	e = &ephemeralTemplate{}
+	if resp = openTestEphemeral(t, e, map[string]interface{}{"template": "text"}); !resp.Diagnostics.HasError() {
+		t.Error("expected an error without a configured provider")
+	}
+}
@@ -0,0 +1,397 @@
+package secretsmanager
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// errFakeThrottled mimics the error returned by the KSM API when requests are throttled.
+var errFakeThrottled = errors.New(`{"error":"throttled","message":"Due to repeated attempts, your request has been throttled."}`)
+
+type fakeVaultRecord struct {
+	uid      string
+	folder   string
+	revision int64
+	dict     map[string]interface{}
+	files    []*core.KeeperFile
+}
+
+// fakeVault is an in-memory ksmClient used to run provider code without a Keeper
+// tenant. Records are returned through core.NewRecordFromJson so they behave like
+// records decrypted from a real API response (folder UIDs, revisions, files).
+type fakeVault struct {
+	mu      sync.Mutex
+	records []*fakeVaultRecord
+	folders []*core.KeeperFolder
+	// throttle is the number of upcoming calls that fail with errFakeThrottled
+	throttle int
+	// err fails every call when set - ex. Keeper can not be reached
+	err error
+	// calls counts calls by method name, throttled ones included
+	calls map[string]int
+}
+
+var _ ksmClient = (*fakeVault)(nil)
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{calls: map[string]int{}}
+}
+
+// fakeCredential returns a base64 KSM config that passes provider validation.
+func fakeCredential() string {
+	config, _ := json.Marshal(map[core.ConfigKey]string{
+		core.KEY_HOSTNAME:    "fake.keepersecurity.com",
+		core.KEY_CLIENT_ID:   "fake-client-id",
+		core.KEY_PRIVATE_KEY: base64.StdEncoding.EncodeToString([]byte("fake-private-key")),
+		core.KEY_APP_KEY:     base64.StdEncoding.EncodeToString([]byte("fake-app-key")),
+	})
+	return base64.StdEncoding.EncodeToString(config)
+}
+
+func (v *fakeVault) call(method string) error {
+	v.calls[method]++
+	if v.err != nil {
+		return v.err
+	}
+	if v.throttle > 0 {
+		v.throttle--
+		return errFakeThrottled
+	}
+	return nil
+}
+
+// AddFolder adds a folder - an empty parentUid makes it a shared folder.
+func (v *fakeVault) AddFolder(parentUid, name string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	uid := core.GenerateUid()
+	v.folders = append(v.folders, &core.KeeperFolder{FolderUid: uid, ParentUid: parentUid, Name: name})
+	return uid
+}
+
+// AddRecord adds a record from its JSON dict (type, title, fields, custom, notes).
+func (v *fakeVault) AddRecord(folderUid string, dict map[string]interface{}) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	uid := core.GenerateUid()
+	v.records = append(v.records, &fakeVaultRecord{uid: uid, folder: folderUid, revision: 1, dict: copyDict(dict)})
+	return uid
+}
+
+// AddFile attaches a file with the given content to a record.
+func (v *fakeVault) AddFile(recordUid, name string, content []byte) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	r := v.findRecord(recordUid)
+	if r == nil {
+		return ""
+	}
+	return v.addFile(r, &core.KeeperFileUpload{Name: name, Title: name, Type: "application/octet-stream", Data: content})
+}
+
+func (v *fakeVault) addFile(r *fakeVaultRecord, upload *core.KeeperFileUpload) string {
+	file := &core.KeeperFile{
+		Uid:          core.GenerateUid(),
+		Name:         upload.Name,
+		Title:        upload.Title,
+		Type:         upload.Type,
+		Size:         len(upload.Data),
+		LastModified: int(time.Now().UnixMilli()),
+		FileData:     upload.Data,
+	}
+	r.files = append(r.files, file)
+	return file.Uid
+}
+
+// Record returns the stored dict of a record, nil if not found.
+func (v *fakeVault) Record(uid string) map[string]interface{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if r := v.findRecord(uid); r != nil {
+		return copyDict(r.dict)
+	}
+	return nil
+}
+
+func (v *fakeVault) findRecord(uid string) *fakeVaultRecord {
+	for _, r := range v.records {
+		if r.uid == uid {
+			return r
+		}
+	}
+	return nil
+}
+
+func (v *fakeVault) findFolder(uid string) *core.KeeperFolder {
+	for _, f := range v.folders {
+		if f.FolderUid == uid {
+			return f
+		}
+	}
+	return nil
+}
+
+// sharedFolderOf returns the top level (shared) folder containing folderUid.
+func (v *fakeVault) sharedFolderOf(folderUid string) string {
+	for f := v.findFolder(folderUid); f != nil; f = v.findFolder(f.ParentUid) {
+		if f.ParentUid == "" {
+			return f.FolderUid
+		}
+	}
+	return folderUid
+}
+
+func (v *fakeVault) toRecord(r *fakeVaultRecord) *core.Record {
+	key, _ := core.GetRandomBytes(32)
+	data, _ := core.EncryptAesGcm([]byte(core.DictToJson(r.dict)), key)
+	recordDict := map[string]interface{}{
+		"recordUid":  r.uid,
+		"data":       core.BytesToBase64(data),
+		"revision":   float64(r.revision),
+		"isEditable": true,
+	}
+	sharedFolder := v.sharedFolderOf(r.folder)
+	if sharedFolder != r.folder {
+		recordDict["innerFolderUid"] = r.folder
+	}
+	record := core.NewRecordFromJson(recordDict, key, sharedFolder)
+	record.Files = append(record.Files, r.files...)
+	return record
+}
+
+func copyDict(dict map[string]interface{}) map[string]interface{} {
+	return core.JsonToDict(core.DictToJson(dict))
+}
+
+func (v *fakeVault) GetSecrets(uids []string) ([]*core.Record, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("GetSecrets"); err != nil {
+		return nil, err
+	}
+	records := []*core.Record{}
+	if len(uids) == 0 {
+		for _, r := range v.records {
+			records = append(records, v.toRecord(r))
+		}
+		return records, nil
+	}
+	for _, uid := range uids {
+		if r := v.findRecord(uid); r != nil {
+			records = append(records, v.toRecord(r))
+		}
+	}
+	return records, nil
+}
+
+func (v *fakeVault) Save(record *core.Record) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("Save"); err != nil {
+		return err
+	}
+	r := v.findRecord(record.Uid)
+	if r == nil {
+		return fmt.Errorf("record not found - UID: %s", record.Uid)
+	}
+	if record.Revision != r.revision {
+		return fmt.Errorf("record UID %s is out of sync - revision %d, expected %d", record.Uid, record.Revision, r.revision)
+	}
+	r.dict = core.JsonToDict(record.RawJson)
+	r.revision++
+	return nil
+}
+
+func (v *fakeVault) DeleteSecrets(recordUids []string) (map[string]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("DeleteSecrets"); err != nil {
+		return nil, err
+	}
+	statuses := map[string]string{}
+	for _, uid := range recordUids {
+		statuses[uid] = "error"
+		for i, r := range v.records {
+			if r.uid == uid {
+				v.records = append(v.records[:i], v.records[i+1:]...)
+				statuses[uid] = "ok"
+				break
+			}
+		}
+	}
+	return statuses, nil
+}
+
+func (v *fakeVault) CreateSecretWithRecordDataUidAndOptions(recUid string, createOptions *core.CreateOptions, recordData *core.RecordCreate, folders []*core.KeeperFolder) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("CreateSecretWithRecordDataUidAndOptions"); err != nil {
+		return "", err
+	}
+	if createOptions == nil || v.findFolder(createOptions.FolderUid) == nil {
+		return "", errors.New("shared folder not found")
+	}
+	folder := createOptions.FolderUid
+	if createOptions.SubFolderUid != "" {
+		if v.findFolder(createOptions.SubFolderUid) == nil {
+			return "", fmt.Errorf("folder not found - UID: %s", createOptions.SubFolderUid)
+		}
+		folder = createOptions.SubFolderUid
+	}
+	if recUid == "" {
+		recUid = core.GenerateUid()
+	} else if v.findRecord(recUid) != nil {
+		return "", fmt.Errorf("record UID %s already exists", recUid)
+	}
+	v.records = append(v.records, &fakeVaultRecord{uid: recUid, folder: folder, revision: 1, dict: core.JsonToDict(recordData.ToJson())})
+	return recUid, nil
+}
+
+func (v *fakeVault) UploadFile(record *core.Record, file *core.KeeperFileUpload) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("UploadFile"); err != nil {
+		return "", err
+	}
+	r := v.findRecord(record.Uid)
+	if r == nil {
+		return "", fmt.Errorf("record not found - UID: %s", record.Uid)
+	}
+	uid := v.addFile(r, file)
+
+	// the owner record lists its files in the fileRef field
+	fields, _ := r.dict["fields"].([]interface{})
+	for _, f := range fields {
+		if field, ok := f.(map[string]interface{}); ok && field["type"] == "fileRef" {
+			values, _ := field["value"].([]interface{})
+			field["value"] = append(values, uid)
+			r.revision++
+			return uid, nil
+		}
+	}
+	r.dict["fields"] = append(fields, map[string]interface{}{"type": "fileRef", "value": []interface{}{uid}})
+	r.revision++
+	return uid, nil
+}
+
+func (v *fakeVault) GetFolders() ([]*core.KeeperFolder, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("GetFolders"); err != nil {
+		return nil, err
+	}
+	folders := make([]*core.KeeperFolder, 0, len(v.folders))
+	for _, f := range v.folders {
+		folder := *f
+		folders = append(folders, &folder)
+	}
+	return folders, nil
+}
+
+func (v *fakeVault) CreateFolder(createOptions core.CreateOptions, folderName string, folders []*core.KeeperFolder) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("CreateFolder"); err != nil {
+		return "", err
+	}
+	parent := createOptions.FolderUid
+	if createOptions.SubFolderUid != "" {
+		parent = createOptions.SubFolderUid
+	}
+	if v.findFolder(parent) == nil {
+		return "", fmt.Errorf("parent folder not found - UID: %s", parent)
+	}
+	uid := core.GenerateUid()
+	v.folders = append(v.folders, &core.KeeperFolder{FolderUid: uid, ParentUid: parent, Name: folderName})
+	return uid, nil
+}
+
+func (v *fakeVault) UpdateFolder(folderUid, folderName string, folders []*core.KeeperFolder) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("UpdateFolder"); err != nil {
+		return err
+	}
+	f := v.findFolder(folderUid)
+	if f == nil {
+		return fmt.Errorf("folder not found - UID: %s", folderUid)
+	}
+	f.Name = folderName
+	return nil
+}
+
+func (v *fakeVault) DeleteFolder(folderUids []string, forceDeletion bool) (map[string]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.call("DeleteFolder"); err != nil {
+		return nil, err
+	}
+	statuses := map[string]string{}
+	for _, uid := range folderUids {
+		if v.findFolder(uid) == nil {
+			statuses[uid] = "error"
+			continue
+		}
+		empty := true
+		for _, r := range v.records {
+			if r.folder == uid {
+				empty = false
+			}
+		}
+		for _, f := range v.folders {
+			if f.ParentUid == uid {
+				empty = false
+			}
+		}
+		if !empty && !forceDeletion {
+			statuses[uid] = "folder is not empty"
+			continue
+		}
+		v.deleteFolderTree(uid)
+		statuses[uid] = "ok"
+	}
+	return statuses, nil
+}
+
+func (v *fakeVault) deleteFolderTree(uid string) {
+	for _, f := range append([]*core.KeeperFolder{}, v.folders...) {
+		if f.ParentUid == uid {
+			v.deleteFolderTree(f.FolderUid)
+		}
+	}
+	records := v.records[:0]
+	for _, r := range v.records {
+		if r.folder != uid {
+			records = append(records, r)
+		}
+	}
+	v.records = records
+	for i, f := range v.folders {
+		if f.FolderUid == uid {
+			v.folders = append(v.folders[:i], v.folders[i+1:]...)
+			break
+		}
+	}
+}
+
+func (v *fakeVault) GetNotation(notation string) ([]interface{}, error) {
+	v.mu.Lock()
+	if err := v.call("GetNotation"); err != nil {
+		v.mu.Unlock()
+		return nil, err
+	}
+	records := []*core.Record{}
+	for _, r := range v.records {
+		records = append(records, v.toRecord(r))
+	}
+	v.mu.Unlock()
+
+	// notation lookup only needs the records - no client state
+	return (&core.SecretsManager{}).FindNotation(records, notation)
+}
@@ -0,0 +1,151 @@
+package secretsmanager
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// File content settings of the data sources returning file_ref.
+const (
+	includeFileContentProviderDescription = "Default of `include_file_content` of the `secretsmanager_record`, `secretsmanager_records` " +
+		"and `secretsmanager_file` data sources. Set to `false` to keep attachment content out of the state and skip downloading attachments " +
+		"unless a data source asks for their content or hash. Default: `true`"
+	includeFileContentDescription = "Download the attachments and store their content in `content_base64`. File metadata is returned either way. " +
+		"Default: the provider `include_file_content` setting"
+	includeFileHashDescription = "Download the attachments and store the SHA-256 hash of their content in `sha256` - it tells when a file " +
+		"changed without keeping its content in the state. Default: `false`"
+	fileNameFilterDescription = "Regex pattern (Go syntax) of the attachment names to download - `content_base64` and `sha256` of " +
+		"the other attachments are empty. Max pattern length: 500 chars."
+	maxFileSizeDescription = "Attachments larger than this many bytes aren't downloaded - their `content_base64` and `sha256` are empty. " +
+		"`0` (default) doesn't limit the size."
+	fileSha256Description = "SHA-256 hash (hex) of the file content - empty unless `include_file_hash` is set and the file is downloaded."
+)
+
+// fileContentOptions decide which attachments of file_ref are downloaded
+// and whether their content and hash are returned. Metadata is always
+// returned - nothing is downloaded when neither content nor hash is.
+type fileContentOptions struct {
+	// IncludeContent returns the content in content_base64
+	IncludeContent bool
+	// IncludeHash returns the SHA-256 hash of the content in sha256
+	IncludeHash bool
+	// NameFilter matches the names of the attachments to download - nil
+	// matches all
+	NameFilter *regexp.Regexp
+	// MaxSize is the size in bytes of the largest attachment to download -
+	// 0 doesn't limit it
+	MaxSize int
+}
+
+// allFileContent downloads and returns the content and hash of every attachment.
+var allFileContent = fileContentOptions{IncludeContent: true, IncludeHash: true}
+
+func schemaIncludeFileContent() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: includeFileContentDescription,
+	}
+}
+
+func schemaIncludeFileHash() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: includeFileHashDescription,
+	}
+}
+
+func schemaFileNameFilter() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.All(validation.StringLenBetween(0, maxRegexPatternLength), validation.StringIsValidRegExp),
+		Description:  fileNameFilterDescription,
+	}
+}
+
+func schemaMaxFileSize() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  maxFileSizeDescription,
+	}
+}
+
+// fileContentOptionsFromSchema reads the file content options of the data
+// source - include_file_content defaults to the provider setting.
+func fileContentOptionsFromSchema(d *schema.ResourceData, provider providerMeta) (fileContentOptions, error) {
+	options := fileContentOptions{IncludeContent: !provider.skipFileContent}
+	// deprecated, but the only way to tell false from unset
+	if include, ok := d.GetOkExists("include_file_content"); ok {
+		options.IncludeContent = include.(bool)
+	}
+	options.IncludeHash = d.Get("include_file_hash").(bool)
+	if pattern := strings.TrimSpace(d.Get("file_name_filter").(string)); pattern != "" {
+		if len(pattern) > maxRegexPatternLength {
+			return options, fmt.Errorf("file_name_filter exceeds maximum length of %d characters (got %d)", maxRegexPatternLength, len(pattern))
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return options, fmt.Errorf("invalid file_name_filter '%s': %v", pattern, err)
+		}
+		options.NameFilter = re
+	}
+	options.MaxSize = d.Get("max_file_size").(int)
+	return options, nil
+}
+
+// download reports whether the attachment is downloaded.
+func (o fileContentOptions) download(file *core.KeeperFile) bool {
+	if !o.IncludeContent && !o.IncludeHash {
+		return false
+	}
+	if o.NameFilter != nil && !o.NameFilter.MatchString(file.Name) {
+		return false
+	}
+	return o.MaxSize <= 0 || file.Size <= o.MaxSize
+}
+
+// getFileItemsDataWithOptions is getFileItemsData downloading only the
+// attachments selected by the options.
+func getFileItemsDataWithOptions(fileItems []*core.KeeperFile, options fileContentOptions) []interface{} {
+	fis := make([]interface{}, len(fileItems))
+	for i, fileItem := range fileItems {
+		fi := map[string]interface{}{
+			"uid":   fileItem.Uid,
+			"title": fileItem.Title,
+			"name":  fileItem.Name,
+			"type":  fileItem.Type,
+			"size":  fileItem.Size,
+			// TF timestamp() uses RFC3339
+			"last_modified":  time.Unix(int64(fileItem.LastModified/1000), 0).Format(time.RFC3339),
+			"content_base64": "",
+			"sha256":         "",
+		}
+		if options.download(fileItem) {
+			// an empty download of a non-empty file failed - no hash
+			if fileData := fileItem.GetFileData(); len(fileData) > 0 || fileItem.Size == 0 {
+				if options.IncludeHash {
+					hash := sha256.Sum256(fileData)
+					fi["sha256"] = hex.EncodeToString(hash[:])
+				}
+				if options.IncludeContent {
+					fi["content_base64"] = base64.StdEncoding.EncodeToString(fileData)
+				}
+			}
+		}
+		fis[i] = fi
+	}
+	return fis
+}
@@ -0,0 +1,131 @@
+package secretsmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func TestDataSourceFileContentOptions(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "file", "title": "keystore"})
+	vault.AddFile(uid, "app.jks", []byte("keystore"))
+	vault.AddFile(uid, "backup.bin", []byte(strings.Repeat("x", 100)))
+	hash := sha256.Sum256([]byte("keystore"))
+	keystoreHash := hex.EncodeToString(hash[:])
+
+	read := func(meta providerMeta, config map[string]interface{}) []interface{} {
+		t.Helper()
+		config["path"] = uid
+		ds := Provider().DataSourcesMap["secretsmanager_file"]
+		d := schema.TestResourceDataRaw(t, ds.Schema, config)
+		if diags := ds.ReadContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("read: %v", diags)
+		}
+		return d.Get("file_ref").([]interface{})
+	}
+	attribute := func(files []interface{}, i int, key string) string {
+		return files[i].(map[string]interface{})[key].(string)
+	}
+
+	files := read(providerMeta{client: vault}, map[string]interface{}{})
+	if attribute(files, 0, "content_base64") != base64.StdEncoding.EncodeToString([]byte("keystore")) || attribute(files, 0, "sha256") != "" {
+		t.Errorf("default file_ref = %v", files[0])
+	}
+	if attribute(files, 1, "content_base64") == "" || attribute(files, 1, "sha256") != "" {
+		t.Errorf("default file_ref = %v", files[1])
+	}
+
+	files = read(providerMeta{client: vault}, map[string]interface{}{"include_file_content": false, "include_file_hash": true, "file_name_filter": `\.jks$`})
+	if attribute(files, 0, "content_base64") != "" || attribute(files, 0, "sha256") != keystoreHash {
+		t.Errorf("file_ref without content = %v", files[0])
+	}
+	if attribute(files, 1, "name") != "backup.bin" || attribute(files, 1, "sha256") != "" {
+		t.Errorf("filtered out file_ref = %v", files[1])
+	}
+
+	// the provider default, overridden by the data source
+	skipping := providerMeta{client: vault, skipFileContent: true}
+	if files = read(skipping, map[string]interface{}{"include_file_hash": true}); attribute(files, 0, "content_base64") != "" || attribute(files, 0, "sha256") != keystoreHash {
+		t.Errorf("file_ref with the provider default = %v", files[0])
+	}
+	files = read(skipping, map[string]interface{}{"include_file_content": true, "max_file_size": 10})
+	if attribute(files, 0, "content_base64") == "" || attribute(files, 1, "content_base64") != "" || attribute(files, 1, "sha256") != "" {
+		t.Errorf("file_ref with max_file_size = %v", files)
+	}
+}
+
+func TestDataSourceRecordsFileContent(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+
+	ds := Provider().DataSourcesMap["secretsmanager_records"]
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"uids":                 []interface{}{uid},
+		"include_file_content": false,
+	})
+	if diags := ds.ReadContext(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	file := d.Get("records.0.file_ref.0").(map[string]interface{})
+	if file["content_base64"] != "" || file["sha256"] != "" || file["name"] != "cert.pem" {
+		t.Errorf("file_ref = %v", file)
+	}
+	if recordJson := d.Get("records_by_uid").(map[string]interface{})[uid].(string); strings.Contains(recordJson, base64.StdEncoding.EncodeToString([]byte("certificate"))) {
+		t.Errorf("records_by_uid has the file content: %s", recordJson)
+	}
+}
+
+func TestDataSourceTypedFileRef(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+
+	ds := Provider().DataSourcesMap["secretsmanager_login"]
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"path": uid})
+	if diags := ds.ReadContext(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	file := d.Get("file_ref.0").(map[string]interface{})
+	if file["name"] != "cert.pem" || file["content_base64"] != base64.StdEncoding.EncodeToString([]byte("certificate")) {
+		t.Errorf("file_ref = %v", file)
+	}
+}
+
+// TestFileItemsWithoutContentNotDownloaded verifies attachments are downloaded
+// only when their content or hash is asked for.
+func TestFileItemsWithoutContentNotDownloaded(t *testing.T) {
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+	}))
+	defer server.Close()
+	file := func() *core.KeeperFile {
+		return &core.KeeperFile{Uid: "file-uid", Name: "backup.bin", Size: 100, F: map[string]interface{}{"url": server.URL}}
+	}
+
+	files := getFileItemsDataWithOptions([]*core.KeeperFile{file()}, fileContentOptions{})
+	if downloads != 0 {
+		t.Errorf("got %d downloads without content or hash", downloads)
+	}
+	if fi := files[0].(map[string]interface{}); fi["name"] != "backup.bin" || fi["content_base64"] != "" || fi["sha256"] != "" {
+		t.Errorf("file_ref = %v", fi)
+	}
+
+	getFileItemsDataWithOptions([]*core.KeeperFile{file()}, fileContentOptions{IncludeHash: true})
+	if downloads != 1 {
+		t.Errorf("got %d downloads with include_file_hash, want 1", downloads)
+	}
+}
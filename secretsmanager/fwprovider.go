@@ -27,7 +27,31 @@ type fwProvider struct {
 }
 
 type fwProviderModel struct {
-	Credential types.String `tfsdk:"credential"`
+	Credential            types.String `tfsdk:"credential"`
+	Hostname              types.String `tfsdk:"hostname"`
+	ProxyUrl              types.String `tfsdk:"proxy_url"`
+	CaCertFile            types.String `tfsdk:"ca_cert_file"`
+	CaCertPem             types.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify    types.Bool   `tfsdk:"insecure_skip_verify"`
+	CacheFile             types.String `tfsdk:"cache_file"`
+	FallbackToCache       types.Bool   `tfsdk:"fallback_to_cache"`
+	ArchiveFolderUid      types.String `tfsdk:"archive_folder_uid"`
+	AuditLogPath          types.String `tfsdk:"audit_log_path"`
+	MaxConcurrentRequests types.Int64  `tfsdk:"max_concurrent_requests"`
+	ReadOnly              types.Bool   `tfsdk:"read_only"`
+	AllowedWriteFolders   types.List   `tfsdk:"allowed_write_folders"`
+	UnmanagedFields       types.String `tfsdk:"unmanaged_fields"`
+	IncludeFileContent    types.Bool   `tfsdk:"include_file_content"`
+	PasswordPolicy        types.List   `tfsdk:"password_policy"`
+}
+
+type fwPasswordPolicyModel struct {
+	MinLength           types.Int64  `tfsdk:"min_length"`
+	MinCaps             types.Int64  `tfsdk:"min_caps"`
+	MinLowercase        types.Int64  `tfsdk:"min_lowercase"`
+	MinDigits           types.Int64  `tfsdk:"min_digits"`
+	MinSpecial          types.Int64  `tfsdk:"min_special"`
+	ForbiddenCharacters types.String `tfsdk:"forbidden_characters"`
 }
 
 func NewFWProvider() provider.Provider {
@@ -47,6 +71,97 @@ func (p *fwProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *p
 				Sensitive:   true,
 				Description: "Credential to use for Secrets Manager authentication. Can also be sourced from the `KEEPER_CREDENTIAL` environment variable.",
 			},
+			"hostname": fwschema.StringAttribute{
+				Optional:    true,
+				Description: hostnameDescription,
+			},
+			"proxy_url": fwschema.StringAttribute{
+				Optional:    true,
+				Description: proxyUrlDescription,
+			},
+			"ca_cert_file": fwschema.StringAttribute{
+				Optional:    true,
+				Description: caCertFileDescription,
+			},
+			"ca_cert_pem": fwschema.StringAttribute{
+				Optional:    true,
+				Description: caCertPemDescription,
+			},
+			"insecure_skip_verify": fwschema.BoolAttribute{
+				Optional:    true,
+				Description: insecureSkipVerifyDescription,
+			},
+			"cache_file": fwschema.StringAttribute{
+				Optional:    true,
+				Description: cacheFileDescription,
+			},
+			"fallback_to_cache": fwschema.BoolAttribute{
+				Optional:    true,
+				Description: fallbackToCacheDescription,
+			},
+			"archive_folder_uid": fwschema.StringAttribute{
+				Optional:    true,
+				Description: archiveFolderUidDescription,
+			},
+			"audit_log_path": fwschema.StringAttribute{
+				Optional:    true,
+				Description: auditLogPathDescription,
+			},
+			"max_concurrent_requests": fwschema.Int64Attribute{
+				Optional:    true,
+				Description: maxConcurrentRequestsDescription,
+			},
+			"read_only": fwschema.BoolAttribute{
+				Optional:    true,
+				Description: readOnlyDescription,
+			},
+			"allowed_write_folders": fwschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: allowedWriteFoldersDescription,
+			},
+			"unmanaged_fields": fwschema.StringAttribute{
+				Optional:    true,
+				Description: unmanagedFieldsDescription,
+				Validators:  unmanagedFieldsValidators,
+			},
+			"include_file_content": fwschema.BoolAttribute{
+				Optional:    true,
+				Description: includeFileContentProviderDescription,
+			},
+		},
+		Blocks: map[string]fwschema.Block{
+			"password_policy": fwschema.ListNestedBlock{
+				Description: passwordPolicyDescription,
+				NestedObject: fwschema.NestedBlockObject{
+					Attributes: map[string]fwschema.Attribute{
+						"min_length": fwschema.Int64Attribute{
+							Optional:    true,
+							Description: minLengthDescription,
+						},
+						"min_caps": fwschema.Int64Attribute{
+							Optional:    true,
+							Description: minCapsDescription,
+						},
+						"min_lowercase": fwschema.Int64Attribute{
+							Optional:    true,
+							Description: minLowercaseDescription,
+						},
+						"min_digits": fwschema.Int64Attribute{
+							Optional:    true,
+							Description: minDigitsDescription,
+						},
+						"min_special": fwschema.Int64Attribute{
+							Optional:    true,
+							Description: minSpecialDescription,
+						},
+						"forbidden_characters": fwschema.StringAttribute{
+							Optional:    true,
+							Description: forbiddenCharactersDescription,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -76,9 +191,71 @@ func (p *fwProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		)
 		return
 	}
+	network := networkSettings{
+		Hostname:           config.Hostname.ValueString(),
+		ProxyUrl:           config.ProxyUrl.ValueString(),
+		CaCertFile:         config.CaCertFile.ValueString(),
+		CaCertPem:          config.CaCertPem.ValueString(),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+	if err := configureNetwork(ksmConfig, network.withEnvDefaults()); err != nil {
+		resp.Diagnostics.AddError("Invalid Network Settings", err.Error())
+		return
+	}
 
-	client := core.NewSecretsManager(&core.ClientOptions{Config: ksmConfig})
-	p.meta = providerMeta{client: client}
+	cache := cacheSettings{
+		CacheFile:       config.CacheFile.ValueString(),
+		FallbackToCache: config.FallbackToCache.ValueBool(),
+	}
+	policy := writePolicy{ReadOnly: config.ReadOnly.ValueBool()}
+	resp.Diagnostics.Append(config.AllowedWriteFolders.ElementsAs(ctx, &policy.AllowedFolders, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	auditLogPath := config.AuditLogPath.ValueString()
+	if auditLogPath == "" {
+		auditLogPath = envDefault("KEEPER_AUDIT_LOG_PATH")
+	}
+	limit, err := maxConcurrentRequests(int(config.MaxConcurrentRequests.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Concurrency Settings", err.Error())
+		return
+	}
+	client, err := configureAuditLog(configureWritePolicy(configureSharedClient(newKsmClient(ksmConfig), ksmConfig, limit), policy.withEnvDefaults()), auditLogPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Audit Log Settings", err.Error())
+		return
+	}
+	client, err = configureCache(client, ksmConfig, cache.withEnvDefaults())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cache Settings", err.Error())
+		return
+	}
+	archiveFolderUid := config.ArchiveFolderUid.ValueString()
+	if archiveFolderUid == "" {
+		archiveFolderUid = envDefault("KEEPER_ARCHIVE_FOLDER_UID")
+	}
+	var policies []fwPasswordPolicyModel
+	resp.Diagnostics.Append(config.PasswordPolicy.ElementsAs(ctx, &policies, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var passwordRules *passwordPolicy
+	if len(policies) > 0 {
+		passwordRules, err = newPasswordPolicy(policies[0].passwordPolicy())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Password Policy", err.Error())
+			return
+		}
+	}
+	p.meta = providerMeta{
+		client:           client,
+		unmanagedFields:  config.UnmanagedFields.ValueString(),
+		archiveFolderUid: strings.TrimSpace(archiveFolderUid),
+		passwordPolicy:   passwordRules,
+		// null is the default - true
+		skipFileContent: !config.IncludeFileContent.IsNull() && !config.IncludeFileContent.ValueBool(),
+	}
 
 	resp.EphemeralResourceData = p.meta
 }
@@ -100,10 +277,13 @@ func (p *fwProvider) Functions(_ context.Context) []func() function.Function {
 
 // EphemeralResources registers all ephemeral resources served by the Framework provider.
 func (p *fwProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{
+	resources := []func() ephemeral.EphemeralResource{
 		NewEphemeralLogin,
 		NewEphemeralField,
 		NewEphemeralRecord,
+		NewEphemeralRecords,
+		NewEphemeralFolderRecords,
+		NewEphemeralTemplate,
 		NewEphemeralDatabaseCredentials,
 		NewEphemeralServerCredentials,
 		NewEphemeralSshKeys,
@@ -121,11 +301,29 @@ func (p *fwProvider) EphemeralResources(_ context.Context) []func() ephemeral.Ep
 		NewEphemeralSoftwareLicense,
 		NewEphemeralSsnCard,
 		NewEphemeralFile,
+		NewEphemeralTempFile,
 		NewEphemeralPamUser,
 		NewEphemeralPamMachine,
 		NewEphemeralPamDatabase,
 		NewEphemeralPamDirectory,
 		NewEphemeralPamRemoteBrowser,
+		NewEphemeralPassword,
+	}
+	// every Open logs its KSM API calls
+	for i, newResource := range resources {
+		resources[i] = withApiLogging(newResource)
+	}
+	return resources
+}
+
+func (m fwPasswordPolicyModel) passwordPolicy() passwordPolicy {
+	return passwordPolicy{
+		MinLength:           int(m.MinLength.ValueInt64()),
+		MinCaps:             int(m.MinCaps.ValueInt64()),
+		MinLowercase:        int(m.MinLowercase.ValueInt64()),
+		MinDigits:           int(m.MinDigits.ValueInt64()),
+		MinSpecial:          int(m.MinSpecial.ValueInt64()),
+		ForbiddenCharacters: m.ForbiddenCharacters.ValueString(),
 	}
 }
 
@@ -0,0 +1,43 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// unmanagedFieldsValidators mirror the SDKv2 validation of unmanaged_fields.
+var unmanagedFieldsValidators = []validator.String{stringOneOf(UnmanagedFieldsRemove, UnmanagedFieldsPreserve)}
+
+// stringOneOfValidator is the framework counterpart of the SDKv2
+// validation.StringInSlice so both muxed provider schemas accept the same values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %q", v.values)
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value))
+}
@@ -0,0 +1,645 @@
+package secretsmanager
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	_ "unsafe" // go:linkname
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// ksmServerPublicKeys is the SDK table of Keeper server public keys, by key id.
+// Clients encrypt every request for one of these keys, so the test server
+// registers its own key to be able to read requests.
+//
+//go:linkname ksmServerPublicKeys github.com/keeper-security/secrets-manager-go/core.keeperServerPublicKeys
+var ksmServerPublicKeys map[string]string
+
+const ksmTestServerKeyId = "99"
+
+// ksmTestServerKey is the server private key for ksmTestServerKeyId - shared by
+// all test servers so the SDK key table is only written once.
+var ksmTestServerKey = sync.OnceValue(func() *core.PrivateKey {
+	key, err := core.GeneratePrivateKeyEcc()
+	if err != nil {
+		panic(err)
+	}
+	publicKey, err := core.EcPublicKeyToEncodedPoint((*ecdsa.PublicKey)(key.GetPublicKey()))
+	if err != nil {
+		panic(err)
+	}
+	ksmServerPublicKeys[ksmTestServerKeyId] = core.BytesToUrlSafeStr(publicKey)
+	return &key
+})
+
+type ksmTestFolder struct {
+	uid    string
+	parent string
+	name   string
+	key    []byte
+}
+
+type ksmTestFile struct {
+	uid     string
+	key     []byte
+	meta    map[string]interface{}
+	content []byte
+}
+
+type ksmTestRecord struct {
+	uid      string
+	folder   string
+	key      []byte
+	data     string // record JSON
+	revision int64
+	files    []*ksmTestFile
+}
+
+// ksmTestServer is an HTTPS server speaking the Secrets Manager API for a single
+// application - requests and responses are encrypted and signed like with the
+// Keeper backend, so the real SDK client can be tested without network access.
+type ksmTestServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	appKey    []byte
+	clientId  string
+	clientKey *core.PrivateKey
+	ownerKey  *core.PrivateKey
+	folders   []*ksmTestFolder
+	records   []*ksmTestRecord
+	// throttle is the number of upcoming API calls rejected as throttled
+	throttle int
+	// calls counts API calls by endpoint, throttled ones included
+	calls map[string]int
+}
+
+// newKsmTestServer starts a test server and routes the SDK traffic for it
+// through defaultKsmTransport, trusting the server certificate.
+func newKsmTestServer(t *testing.T) *ksmTestServer {
+	t.Helper()
+	ksmTestServerKey()
+
+	clientKey, err := core.GeneratePrivateKeyEcc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownerKey, err := core.GeneratePrivateKeyEcc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	appKey, _ := core.GetRandomBytes(32)
+	clientId, _ := core.GetRandomBytes(64)
+
+	s := &ksmTestServer{
+		appKey:    appKey,
+		clientId:  core.BytesToBase64(clientId),
+		clientKey: &clientKey,
+		ownerKey:  &ownerKey,
+		calls:     map[string]int{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rest/sm/v1/", s.handleApi)
+	mux.HandleFunc("/files/", s.handleFile)
+	s.Server = httptest.NewTLSServer(mux)
+	t.Cleanup(s.Close)
+
+	installKsmTransport()
+	defaultKsmTransport.setHostTransport(s.host(), nil)
+	previous := defaultKsmTransport.setBase(s.Client().Transport)
+	t.Cleanup(func() { defaultKsmTransport.setBase(previous) })
+
+	return s
+}
+
+// Credential returns the base64 KSM config of the server application. The
+// hostname has no port - use Hostname() for the provider `hostname` setting.
+func (s *ksmTestServer) Credential() string {
+	privateKey, _ := x509.MarshalPKCS8PrivateKey((*ecdsa.PrivateKey)(s.clientKey))
+	ownerPublicKey, _ := core.EcPublicKeyToEncodedPoint((*ecdsa.PublicKey)(s.ownerKey.GetPublicKey()))
+	config, _ := json.Marshal(map[core.ConfigKey]string{
+		core.KEY_HOSTNAME:             s.host(),
+		core.KEY_CLIENT_ID:            s.clientId,
+		core.KEY_PRIVATE_KEY:          core.BytesToBase64(privateKey),
+		core.KEY_APP_KEY:              core.BytesToBase64(s.appKey),
+		core.KEY_SERVER_PUBLIC_KEY_ID: ksmTestServerKeyId,
+		core.KEY_OWNER_PUBLIC_KEY:     core.BytesToBase64(ownerPublicKey),
+	})
+	return base64.StdEncoding.EncodeToString(config)
+}
+
+// Hostname returns the server URL, including the port.
+func (s *ksmTestServer) Hostname() string {
+	return s.URL
+}
+
+func (s *ksmTestServer) host() string {
+	u, _ := url.Parse(s.URL)
+	return u.Hostname()
+}
+
+// AddFolder adds a folder - an empty parentUid makes it a shared folder.
+func (s *ksmTestServer) AddFolder(parentUid, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, _ := core.GetRandomBytes(32)
+	folder := &ksmTestFolder{uid: core.GenerateUid(), parent: parentUid, name: name, key: key}
+	s.folders = append(s.folders, folder)
+	return folder.uid
+}
+
+// AddRecord adds a record from its JSON dict (type, title, fields, custom, notes).
+func (s *ksmTestServer) AddRecord(folderUid string, dict map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, _ := core.GetRandomBytes(32)
+	record := &ksmTestRecord{uid: core.GenerateUid(), folder: folderUid, key: key, data: core.DictToJson(dict), revision: 1}
+	s.records = append(s.records, record)
+	return record.uid
+}
+
+// AddFile attaches a file with the given content to a record.
+func (s *ksmTestServer) AddFile(recordUid, name string, content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.findRecord(recordUid)
+	if r == nil {
+		return ""
+	}
+	key, _ := core.GetRandomBytes(32)
+	file := &ksmTestFile{
+		uid: core.GenerateUid(),
+		key: key,
+		meta: map[string]interface{}{
+			"name":         name,
+			"title":        name,
+			"type":         "application/octet-stream",
+			"size":         len(content),
+			"lastModified": time.Now().UnixMilli(),
+		},
+		content: content,
+	}
+	r.files = append(r.files, file)
+	return file.uid
+}
+
+// Record returns the stored dict of a record, nil if not found.
+func (s *ksmTestServer) Record(uid string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.findRecord(uid); r != nil {
+		return core.JsonToDict(r.data)
+	}
+	return nil
+}
+
+// Folder returns the name and parent of a folder, found=false if not found.
+func (s *ksmTestServer) Folder(uid string) (name, parentUid string, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f := s.findFolder(uid); f != nil {
+		return f.name, f.parent, true
+	}
+	return "", "", false
+}
+
+func (s *ksmTestServer) findRecord(uid string) *ksmTestRecord {
+	for _, r := range s.records {
+		if r.uid == uid {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *ksmTestServer) findFolder(uid string) *ksmTestFolder {
+	for _, f := range s.folders {
+		if f.uid == uid {
+			return f
+		}
+	}
+	return nil
+}
+
+// sharedFolderOf returns the top level (shared) folder containing folderUid.
+func (s *ksmTestServer) sharedFolderOf(folderUid string) *ksmTestFolder {
+	for f := s.findFolder(folderUid); f != nil; f = s.findFolder(f.parent) {
+		if f.parent == "" {
+			return f
+		}
+	}
+	return nil
+}
+
+// ksmApiError is returned to the client unencrypted, like backend errors.
+type ksmApiError struct {
+	status     int
+	resultCode string
+	message    string
+}
+
+func (e *ksmApiError) Error() string {
+	return e.resultCode + ": " + e.message
+}
+
+func (s *ksmTestServer) handleApi(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/rest/sm/v1/")
+	payload, transmissionKey, err := s.decryptRequest(r)
+	var response interface{}
+	if err == nil {
+		s.mu.Lock()
+		s.calls[endpoint]++
+		if s.throttle > 0 {
+			s.throttle--
+			err = &ksmApiError{http.StatusForbidden, "throttled", "Due to repeated attempts, your request has been throttled."}
+		} else {
+			response, err = s.dispatch(endpoint, payload)
+		}
+		s.mu.Unlock()
+	}
+
+	if err != nil {
+		apiErr := &ksmApiError{}
+		if !errors.As(err, &apiErr) {
+			apiErr = &ksmApiError{http.StatusBadRequest, "error", err.Error()}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(apiErr.status)
+		fmt.Fprint(w, core.DictToJson(map[string]interface{}{"result_code": apiErr.resultCode, "message": apiErr.message}))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if response == nil {
+		return
+	}
+	body, _ := json.Marshal(response)
+	encrypted, _ := core.EncryptAesGcm(body, transmissionKey)
+	w.Write(encrypted)
+}
+
+// decryptRequest checks the transmission key and signature and returns the decrypted payload.
+func (s *ksmTestServer) decryptRequest(r *http.Request) (map[string]interface{}, []byte, error) {
+	if keyId := r.Header.Get("PublicKeyId"); keyId != ksmTestServerKeyId {
+		return nil, nil, &ksmApiError{http.StatusBadRequest, "key", "invalid key id " + keyId}
+	}
+	encryptedKey := core.Base64ToBytes(r.Header.Get("TransmissionKey"))
+	if len(encryptedKey) <= 65 {
+		return nil, nil, errors.New("invalid transmission key")
+	}
+	ephemeralKey, err := core.EcPublicKeyFromEncodedPoint(encryptedKey[:65])
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedKey, err := core.ECDH(*ksmTestServerKey(), ephemeralKey.(core.PublicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	transmissionKey, err := core.Decrypt(encryptedKey[65:], sharedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid transmission key: %w", err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature := core.Base64ToBytes(strings.TrimPrefix(r.Header.Get("Authorization"), "Signature "))
+	signed := append(append([]byte{}, encryptedKey...), body...)
+	if err := core.Verify(signed, signature, s.clientKey.GetPublicKey()); err != nil {
+		return nil, nil, &ksmApiError{http.StatusUnauthorized, "access_denied", "signature is invalid"}
+	}
+
+	payloadJson, err := core.Decrypt(body, transmissionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := core.JsonToDict(string(payloadJson))
+	if clientId, _ := payload["clientId"].(string); clientId != s.clientId {
+		return nil, nil, &ksmApiError{http.StatusUnauthorized, "access_denied", "unknown client id"}
+	}
+	return payload, transmissionKey, nil
+}
+
+func (s *ksmTestServer) dispatch(endpoint string, payload map[string]interface{}) (interface{}, error) {
+	switch endpoint {
+	case "get_secret":
+		return s.getSecret(payload), nil
+	case "get_folders":
+		return s.getFolders(), nil
+	case "update_secret":
+		return nil, s.updateSecret(payload)
+	case "create_secret":
+		return nil, s.createSecret(payload)
+	case "delete_secret":
+		return s.deleteSecret(payload), nil
+	case "create_folder":
+		return nil, s.createFolder(payload)
+	case "update_folder":
+		return nil, s.updateFolder(payload)
+	case "delete_folder":
+		return s.deleteFolder(payload), nil
+	}
+	return nil, &ksmApiError{http.StatusNotFound, "not_found", "unknown endpoint " + endpoint}
+}
+
+func payloadStrings(payload map[string]interface{}, key string) []string {
+	values := []string{}
+	if items, ok := payload[key].([]interface{}); ok {
+		for _, item := range items {
+			if value, ok := item.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func payloadString(payload map[string]interface{}, key string) string {
+	value, _ := payload[key].(string)
+	return value
+}
+
+func encryptGcmBase64(data, key []byte) string {
+	encrypted, _ := core.EncryptAesGcm(data, key)
+	return core.BytesToBase64(encrypted)
+}
+
+func encryptCbcUrlSafe(data, key []byte) string {
+	encrypted, _ := core.EncryptAesCbc(data, key)
+	return core.BytesToUrlSafeStr(encrypted)
+}
+
+func (s *ksmTestServer) getSecret(payload map[string]interface{}) map[string]interface{} {
+	requested := payloadStrings(payload, "requestedRecords")
+	folders := []interface{}{}
+	for _, sf := range s.folders {
+		if sf.parent != "" {
+			continue
+		}
+		records := []interface{}{}
+		for _, r := range s.records {
+			if shared := s.sharedFolderOf(r.folder); shared == nil || shared.uid != sf.uid {
+				continue
+			}
+			if len(requested) > 0 && !containsString(requested, r.uid) {
+				continue
+			}
+			records = append(records, s.recordJson(r, sf))
+		}
+		if len(records) == 0 && len(requested) > 0 {
+			continue
+		}
+		folders = append(folders, map[string]interface{}{
+			"folderUid": sf.uid,
+			"folderKey": encryptGcmBase64(sf.key, s.appKey),
+			"records":   records,
+		})
+	}
+	return map[string]interface{}{"records": []interface{}{}, "folders": folders}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ksmTestServer) recordJson(r *ksmTestRecord, sharedFolder *ksmTestFolder) map[string]interface{} {
+	files := []interface{}{}
+	for _, f := range r.files {
+		files = append(files, map[string]interface{}{
+			"fileUid": f.uid,
+			"fileKey": encryptGcmBase64(f.key, r.key),
+			"data":    encryptGcmBase64([]byte(core.DictToJson(f.meta)), f.key),
+			"url":     s.URL + "/files/" + f.uid,
+		})
+	}
+	record := map[string]interface{}{
+		"recordUid":  r.uid,
+		"recordKey":  encryptGcmBase64(r.key, sharedFolder.key),
+		"data":       encryptGcmBase64([]byte(r.data), r.key),
+		"revision":   r.revision,
+		"isEditable": true,
+		"files":      files,
+	}
+	if r.folder != sharedFolder.uid {
+		record["innerFolderUid"] = r.folder
+	}
+	return record
+}
+
+func (s *ksmTestServer) getFolders() map[string]interface{} {
+	// shared folders first - the client needs their keys to decrypt subfolder keys
+	folders := []interface{}{}
+	for _, f := range s.folders {
+		if f.parent == "" {
+			folders = append(folders, map[string]interface{}{
+				"folderUid": f.uid,
+				"folderKey": encryptGcmBase64(f.key, s.appKey),
+				"data":      encryptCbcUrlSafe([]byte(core.DictToJson(map[string]interface{}{"name": f.name})), f.key),
+			})
+		}
+	}
+	for _, f := range s.folders {
+		if f.parent != "" {
+			folders = append(folders, map[string]interface{}{
+				"folderUid": f.uid,
+				"parent":    f.parent,
+				"folderKey": encryptCbcUrlSafe(f.key, s.sharedFolderOf(f.uid).key),
+				"data":      encryptCbcUrlSafe([]byte(core.DictToJson(map[string]interface{}{"name": f.name})), f.key),
+			})
+		}
+	}
+	return map[string]interface{}{"folders": folders}
+}
+
+func (s *ksmTestServer) updateSecret(payload map[string]interface{}) error {
+	uid := payloadString(payload, "recordUid")
+	r := s.findRecord(uid)
+	if r == nil {
+		return &ksmApiError{http.StatusBadRequest, "not_found", "record not found - UID: " + uid}
+	}
+	if revision, _ := payload["revision"].(float64); int64(revision) != r.revision {
+		return &ksmApiError{http.StatusBadRequest, "out_of_sync", fmt.Sprintf("record revision %d is out of sync, current revision %d", int64(revision), r.revision)}
+	}
+	data, err := core.Decrypt(core.Base64ToBytes(payloadString(payload, "data")), r.key)
+	if err != nil {
+		return err
+	}
+	r.data = string(data)
+	r.revision++
+	return nil
+}
+
+func (s *ksmTestServer) createSecret(payload map[string]interface{}) error {
+	uid := payloadString(payload, "recordUid")
+	if s.findRecord(uid) != nil {
+		return &ksmApiError{http.StatusBadRequest, "already_exists", "record UID already exists - UID: " + uid}
+	}
+	sharedFolder := s.findFolder(payloadString(payload, "folderUid"))
+	if sharedFolder == nil || sharedFolder.parent != "" {
+		return &ksmApiError{http.StatusBadRequest, "not_found", "shared folder not found - UID: " + payloadString(payload, "folderUid")}
+	}
+	folderUid := sharedFolder.uid
+	if subFolderUid := payloadString(payload, "subFolderUid"); subFolderUid != "" {
+		if sf := s.sharedFolderOf(subFolderUid); sf == nil || sf.uid != sharedFolder.uid {
+			return &ksmApiError{http.StatusBadRequest, "not_found", "folder not found - UID: " + subFolderUid}
+		}
+		folderUid = subFolderUid
+	}
+	key, err := core.Decrypt(core.Base64ToBytes(payloadString(payload, "folderKey")), sharedFolder.key)
+	if err != nil {
+		return err
+	}
+	data, err := core.Decrypt(core.Base64ToBytes(payloadString(payload, "data")), key)
+	if err != nil {
+		return err
+	}
+	s.records = append(s.records, &ksmTestRecord{uid: uid, folder: folderUid, key: key, data: string(data), revision: 1})
+	return nil
+}
+
+func (s *ksmTestServer) deleteSecret(payload map[string]interface{}) map[string]interface{} {
+	statuses := []interface{}{}
+	for _, uid := range payloadStrings(payload, "recordUids") {
+		status := map[string]interface{}{"recordUid": uid, "responseCode": "error", "errorMessage": "record not found"}
+		for i, r := range s.records {
+			if r.uid == uid {
+				s.records = append(s.records[:i], s.records[i+1:]...)
+				status = map[string]interface{}{"recordUid": uid, "responseCode": "ok"}
+				break
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return map[string]interface{}{"records": statuses}
+}
+
+func (s *ksmTestServer) folderName(data string, key []byte) (string, error) {
+	nameJson, err := core.DecryptAesCbc(core.UrlSafeStrToBytes(data), key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", core.JsonToDict(string(nameJson))["name"]), nil
+}
+
+func (s *ksmTestServer) createFolder(payload map[string]interface{}) error {
+	sharedFolder := s.findFolder(payloadString(payload, "sharedFolderUid"))
+	if sharedFolder == nil || sharedFolder.parent != "" {
+		return &ksmApiError{http.StatusBadRequest, "not_found", "shared folder not found - UID: " + payloadString(payload, "sharedFolderUid")}
+	}
+	parent := sharedFolder.uid
+	if parentUid := payloadString(payload, "parentUid"); parentUid != "" {
+		if sf := s.sharedFolderOf(parentUid); sf == nil || sf.uid != sharedFolder.uid {
+			return &ksmApiError{http.StatusBadRequest, "not_found", "parent folder not found - UID: " + parentUid}
+		}
+		parent = parentUid
+	}
+	key, err := core.DecryptAesCbc(core.UrlSafeStrToBytes(payloadString(payload, "sharedFolderKey")), sharedFolder.key)
+	if err != nil {
+		return err
+	}
+	name, err := s.folderName(payloadString(payload, "data"), key)
+	if err != nil {
+		return err
+	}
+	s.folders = append(s.folders, &ksmTestFolder{uid: payloadString(payload, "folderUid"), parent: parent, name: name, key: key})
+	return nil
+}
+
+func (s *ksmTestServer) updateFolder(payload map[string]interface{}) error {
+	f := s.findFolder(payloadString(payload, "folderUid"))
+	if f == nil {
+		return &ksmApiError{http.StatusBadRequest, "not_found", "folder not found - UID: " + payloadString(payload, "folderUid")}
+	}
+	name, err := s.folderName(payloadString(payload, "data"), f.key)
+	if err != nil {
+		return err
+	}
+	f.name = name
+	return nil
+}
+
+func (s *ksmTestServer) deleteFolder(payload map[string]interface{}) map[string]interface{} {
+	force, _ := payload["forceDeletion"].(bool)
+	statuses := []interface{}{}
+	for _, uid := range payloadStrings(payload, "folderUids") {
+		status := map[string]interface{}{"folderUid": uid, "responseCode": "ok"}
+		if s.findFolder(uid) == nil {
+			status = map[string]interface{}{"folderUid": uid, "responseCode": "error", "errorMessage": "folder not found"}
+		} else if !force && !s.folderEmpty(uid) {
+			status = map[string]interface{}{"folderUid": uid, "responseCode": "error", "errorMessage": "folder is not empty"}
+		} else {
+			s.deleteFolderTree(uid)
+		}
+		statuses = append(statuses, status)
+	}
+	return map[string]interface{}{"folders": statuses}
+}
+
+func (s *ksmTestServer) folderEmpty(uid string) bool {
+	for _, r := range s.records {
+		if r.folder == uid {
+			return false
+		}
+	}
+	for _, f := range s.folders {
+		if f.parent == uid {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ksmTestServer) deleteFolderTree(uid string) {
+	for _, f := range append([]*ksmTestFolder{}, s.folders...) {
+		if f.parent == uid {
+			s.deleteFolderTree(f.uid)
+		}
+	}
+	records := s.records[:0]
+	for _, r := range s.records {
+		if r.folder != uid {
+			records = append(records, r)
+		}
+	}
+	s.records = records
+	for i, f := range s.folders {
+		if f.uid == uid {
+			s.folders = append(s.folders[:i], s.folders[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *ksmTestServer) handleFile(w http.ResponseWriter, r *http.Request) {
+	uid := strings.TrimPrefix(r.URL.Path, "/files/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range s.records {
+		for _, f := range record.files {
+			if f.uid == uid {
+				encrypted, _ := core.EncryptAesGcm(f.content, f.key)
+				w.Write(encrypted)
+				return
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
@@ -0,0 +1,313 @@
+package secretsmanager
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// ksmLogSubsystem is the tflog subsystem of the KSM API calls. Its level is
+// set by ksmLogLevelEnv, else TF_LOG_PROVIDER or TF_LOG.
+const (
+	ksmLogSubsystem = "ksm"
+	ksmLogLevelEnv  = "TF_LOG_PROVIDER_SECRETSMANAGER_KSM"
+)
+
+// logMaskedKeys are log field keys whose values are always masked.
+var logMaskedKeys = []string{"value", "password", "secret", "notes", "credential"}
+
+// logMaskedRegexes mask record field values in logged messages and errors -
+// ex. record JSON echoed back in an SDK error.
+var logMaskedRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`"value"\s*:\s*\[[^\]]*\]`),
+	regexp.MustCompile(`"(password|secret|privateKey|notes)"\s*:\s*"[^"]*"`),
+}
+
+// logMinMaskedValueLength skips masking short field values (ex. "true") that
+// would mask unrelated words in the logs.
+const logMinMaskedValueLength = 6
+
+// apiLog is the logging context of a Terraform read or apply. It counts the
+// API calls for the summary logged when the operation ends.
+type apiLog struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	calls     map[string]int
+	retries   int
+	throttled time.Duration
+	masked    map[string]bool
+}
+
+// apiScope carries the apiLog of an operation with its client so the API
+// helpers can log to it.
+type apiScope struct {
+	ksmClient
+	*apiLog
+}
+
+func (s *apiScope) unwrap() ksmClient { return s.ksmClient }
+
+// newApiScope scopes the client to the context of a Terraform operation.
+func newApiScope(ctx context.Context, client ksmClient) *apiScope {
+	s := &apiScope{ksmClient: client, apiLog: &apiLog{calls: map[string]int{}, masked: map[string]bool{}}}
+	s.setContext(ctx)
+	return s
+}
+
+func (s *apiLog) setContext(ctx context.Context) {
+	ctx = tflog.NewSubsystem(ctx, ksmLogSubsystem, tflog.WithLevelFromEnv(ksmLogLevelEnv))
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, ksmLogSubsystem, logMaskedKeys...)
+	ctx = tflog.SubsystemMaskLogRegexes(ctx, ksmLogSubsystem, logMaskedRegexes...)
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+}
+
+// context returns the logging context, nil-safe for clients without a scope.
+func (s *apiLog) context() context.Context {
+	if s == nil {
+		return context.Background()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx
+}
+
+// maskRecords masks the field values of fetched records in later log entries.
+func (s *apiLog) maskRecords(records []*core.Record) {
+	if s == nil {
+		return
+	}
+	values := []string{}
+	for _, r := range records {
+		for _, section := range []string{"fields", "custom"} {
+			fields, _ := r.RecordDict[section].([]interface{})
+			for _, f := range fields {
+				if field, ok := f.(map[string]interface{}); ok {
+					values = appendLogValues(values, field["value"])
+				}
+			}
+		}
+		values = appendLogValues(values, r.RecordDict["notes"])
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := []string{}
+	for _, v := range values {
+		if !s.masked[v] {
+			s.masked[v] = true
+			added = append(added, v)
+		}
+	}
+	if len(added) > 0 {
+		s.ctx = tflog.SubsystemMaskLogStrings(s.ctx, ksmLogSubsystem, added...)
+	}
+}
+
+func appendLogValues(values []string, value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if len(v) >= logMinMaskedValueLength {
+			values = append(values, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			values = appendLogValues(values, item)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			values = appendLogValues(values, item)
+		}
+	}
+	return values
+}
+
+// summary logs the API call counts of the scope.
+func (s *apiLog) summary(operation string, started time.Time) {
+	s.mu.Lock()
+	total := 0
+	calls := map[string]interface{}{}
+	for name, n := range s.calls {
+		calls[name] = n
+		total += n
+	}
+	fields := map[string]interface{}{
+		"operation":      operation,
+		"api_calls":      total,
+		"calls":          calls,
+		"retries":        s.retries,
+		"throttled_wait": s.throttled.String(),
+		"duration_ms":    time.Since(started).Milliseconds(),
+	}
+	ctx := s.ctx
+	s.mu.Unlock()
+	tflog.SubsystemDebug(ctx, ksmLogSubsystem, "KSM API call summary", fields)
+}
+
+// apiLogOf returns the apiLog of the client, nil if it has none.
+func apiLogOf(client ksmClient) *apiLog {
+	for client != nil {
+		if s, ok := client.(*apiScope); ok {
+			return s.apiLog
+		}
+		w, ok := client.(wrappedClient)
+		if !ok {
+			return nil
+		}
+		client = w.unwrap()
+	}
+	return nil
+}
+
+// apiCall logs one KSM API call made by a helper, retries included.
+type apiCall struct {
+	scope     *apiLog
+	operation string
+	fields    map[string]interface{}
+	started   time.Time
+	attempts  int
+}
+
+// startApiCall starts logging the call - fields must not hold secret values.
+func startApiCall(client ksmClient, operation string, fields map[string]interface{}) *apiCall {
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields["operation"] = operation
+	c := &apiCall{scope: apiLogOf(client), operation: operation, fields: fields, started: time.Now()}
+	tflog.SubsystemTrace(c.scope.context(), ksmLogSubsystem, "KSM API call started", fields)
+	return c
+}
+
+// throttled logs the wait before retrying a throttled call.
+func (c *apiCall) throttled(wait time.Duration) {
+	c.attempts++
+	if c.scope != nil {
+		c.scope.mu.Lock()
+		c.scope.retries++
+		c.scope.throttled += wait
+		c.scope.mu.Unlock()
+	}
+	tflog.SubsystemWarn(c.scope.context(), ksmLogSubsystem, "KSM API call throttled - waiting before retrying", map[string]interface{}{
+		"operation": c.operation,
+		"attempt":   c.attempts,
+		"wait":      wait.String(),
+	})
+}
+
+// done logs the result of the call.
+func (c *apiCall) done(err error) {
+	if c.scope != nil {
+		c.scope.mu.Lock()
+		c.scope.calls[c.operation] += c.attempts + 1
+		c.scope.mu.Unlock()
+	}
+	c.fields["duration_ms"] = time.Since(c.started).Milliseconds()
+	c.fields["retries"] = c.attempts
+	if err != nil {
+		c.fields["error"] = err.Error()
+		tflog.SubsystemError(c.scope.context(), ksmLogSubsystem, "KSM API call failed", c.fields)
+		return
+	}
+	tflog.SubsystemDebug(c.scope.context(), ksmLogSubsystem, "KSM API call", c.fields)
+}
+
+// logApiCalls scopes the client of an SDK resource or data source operation
+// and logs the API call summary when it ends.
+func logApiCalls(operation string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		provider, ok := m.(providerMeta)
+		if !ok || provider.client == nil {
+			return fn(ctx, d, m)
+		}
+		started := time.Now()
+		scope := newApiScope(ctx, provider.client)
+		provider.client = scope
+		defer scope.summary(operation, started)
+		return fn(ctx, d, provider)
+	}
+}
+
+// loggedEphemeralResource scopes the client of an ephemeral resource to the
+// context of its Open and logs the API call summary. The framework creates a
+// resource per request so the scope isn't shared between requests.
+type loggedEphemeralResource struct {
+	ephemeral.EphemeralResource
+	scope *apiScope
+}
+
+// The framework finds the optional interfaces by type assertion so the
+// wrapper implements them all and forwards to the resource.
+var (
+	_ ephemeral.EphemeralResourceWithConfigure        = &loggedEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew            = &loggedEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose            = &loggedEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig   = &loggedEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigValidators = &loggedEphemeralResource{}
+)
+
+// withApiLogging adds API call logging to the ephemeral resource.
+func withApiLogging(newResource func() ephemeral.EphemeralResource) func() ephemeral.EphemeralResource {
+	return func() ephemeral.EphemeralResource {
+		return &loggedEphemeralResource{EphemeralResource: newResource()}
+	}
+}
+
+func (r *loggedEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if meta, ok := req.ProviderData.(providerMeta); ok && meta.client != nil {
+		r.scope = newApiScope(ctx, meta.client)
+		meta.client = r.scope
+		req.ProviderData = meta
+	}
+	if c, ok := r.EphemeralResource.(ephemeral.EphemeralResourceWithConfigure); ok {
+		c.Configure(ctx, req, resp)
+	}
+}
+
+func (r *loggedEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	if r.scope == nil {
+		r.EphemeralResource.Open(ctx, req, resp)
+		return
+	}
+	metadata := &ephemeral.MetadataResponse{}
+	r.EphemeralResource.Metadata(ctx, ephemeral.MetadataRequest{ProviderTypeName: "secretsmanager"}, metadata)
+	started := time.Now()
+	r.scope.setContext(ctx)
+	defer r.scope.summary("open "+metadata.TypeName, started)
+	r.EphemeralResource.Open(ctx, req, resp)
+}
+
+func (r *loggedEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	if renew, ok := r.EphemeralResource.(ephemeral.EphemeralResourceWithRenew); ok {
+		renew.Renew(ctx, req, resp)
+	}
+}
+
+func (r *loggedEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	if c, ok := r.EphemeralResource.(ephemeral.EphemeralResourceWithClose); ok {
+		c.Close(ctx, req, resp)
+	}
+}
+
+func (r *loggedEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	if v, ok := r.EphemeralResource.(ephemeral.EphemeralResourceWithValidateConfig); ok {
+		v.ValidateConfig(ctx, req, resp)
+	}
+}
+
+func (r *loggedEphemeralResource) ConfigValidators(ctx context.Context) []ephemeral.ConfigValidator {
+	if v, ok := r.EphemeralResource.(ephemeral.EphemeralResourceWithConfigValidators); ok {
+		return v.ConfigValidators(ctx)
+	}
+	return nil
+}
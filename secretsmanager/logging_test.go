@@ -0,0 +1,87 @@
+package secretsmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func decodeTestLogs(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	entries, err := tflogtest.MultilineJSONDecode(output)
+	if err != nil {
+		t.Fatalf("decode logs: %v", err)
+	}
+	return entries
+}
+
+func findTestLog(entries []map[string]interface{}, message string) map[string]interface{} {
+	for _, e := range entries {
+		if e["@message"] == message {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestApiCallLogging(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"hunter2-secret"}}},
+	})
+
+	scope := newApiScope(ctx, vault)
+	vault.throttle = 1
+	if _, err := getSecrets(ctx, scope, []string{uid}); err != nil {
+		t.Fatalf("getSecrets: %v", err)
+	}
+	// field values of fetched records are masked, even in errors
+	call := startApiCall(scope, "Save", map[string]interface{}{"record_uid": uid})
+	call.done(errors.New(`save failed for hunter2-secret {"value":["other-secret"]}`))
+	scope.summary("read secretsmanager_login", call.started)
+
+	if strings.Contains(output.String(), "hunter2-secret") || strings.Contains(output.String(), "other-secret") {
+		t.Errorf("logs contain field values: %s", output.String())
+	}
+	entries := decodeTestLogs(t, &output)
+	if e := findTestLog(entries, "KSM API call throttled - waiting before retrying"); e == nil || e["@level"] != "warn" {
+		t.Errorf("missing throttling entry: %v", entries)
+	}
+	e := findTestLog(entries, "KSM API call")
+	if e == nil || e["operation"] != "GetSecrets" || e["retries"] != float64(1) || e["@module"] != "provider.ksm" {
+		t.Errorf("unexpected API call entry: %v", e)
+	}
+	if e := findTestLog(entries, "KSM API call failed"); e == nil || !strings.Contains(e["error"].(string), "save failed") {
+		t.Errorf("unexpected failed call entry: %v", e)
+	}
+	summary := findTestLog(entries, "KSM API call summary")
+	if summary == nil || summary["api_calls"] != float64(3) || summary["retries"] != float64(1) {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+}
+
+func TestDataSourceLogsApiCallSummary(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+
+	ds := Provider().DataSourcesMap["secretsmanager_login"]
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"path": uid})
+	if diags := ds.ReadContext(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	summary := findTestLog(decodeTestLogs(t, &output), "KSM API call summary")
+	if summary == nil || summary["operation"] != "read secretsmanager_login" || summary["api_calls"] != float64(1) {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+}
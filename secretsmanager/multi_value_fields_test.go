@@ -0,0 +1,385 @@
+package secretsmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// TestNewFieldFromSchemaMultiValue verifies that every value of a multi-value
+// field is written to the record, in configuration order. Previously only
+// values[0] was kept and extra phones/hosts/questions were silently dropped.
+func TestNewFieldFromSchemaMultiValue(t *testing.T) {
+	t.Run("host", func(t *testing.T) {
+		data := []interface{}{map[string]interface{}{
+			"value": []interface{}{
+				map[string]interface{}{"host_name": "db1.example.com", "port": "5432"},
+				map[string]interface{}{"host_name": "db2.example.com", "port": "5433"},
+			},
+		}}
+		field, err := NewFieldFromSchema("host", data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hosts := field.(*core.Hosts)
+		if len(hosts.Value) != 2 {
+			t.Fatalf("expected 2 hosts, got %d", len(hosts.Value))
+		}
+		if hosts.Value[0].Hostname != "db1.example.com" || hosts.Value[1].Hostname != "db2.example.com" {
+			t.Errorf("hosts out of order: %+v", hosts.Value)
+		}
+	})
+
+	t.Run("phone", func(t *testing.T) {
+		data := []interface{}{map[string]interface{}{
+			"value": []interface{}{
+				map[string]interface{}{"number": "555-0001", "type": "Work"},
+				map[string]interface{}{"number": "555-0002", "type": "Mobile"},
+				map[string]interface{}{"number": "555-0003", "type": "Home"},
+			},
+		}}
+		field, err := NewFieldFromSchema("phone", data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		phones := field.(*core.Phones)
+		if len(phones.Value) != 3 {
+			t.Fatalf("expected 3 phones, got %d", len(phones.Value))
+		}
+		for i, want := range []string{"555-0001", "555-0002", "555-0003"} {
+			if phones.Value[i].Number != want {
+				t.Errorf("phone[%d] = %q, want %q", i, phones.Value[i].Number, want)
+			}
+		}
+	})
+
+	t.Run("securityQuestion", func(t *testing.T) {
+		data := []interface{}{map[string]interface{}{
+			"value": []interface{}{
+				map[string]interface{}{"question": "q1", "answer": "a1"},
+				map[string]interface{}{"question": "q2", "answer": "a2"},
+			},
+		}}
+		field, err := NewFieldFromSchema("securityQuestion", data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		questions := field.(*core.SecurityQuestions)
+		if len(questions.Value) != 2 || questions.Value[1].Answer != "a2" {
+			t.Errorf("unexpected security questions: %+v", questions.Value)
+		}
+	})
+}
+
+// TestGetFieldResourceDataMultiValue verifies that managed resources read back
+// every value of a multi-value field while single-value types keep only the first.
+func TestGetFieldResourceDataMultiValue(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "serverCredentials",
+			"fields": []interface{}{
+				map[string]interface{}{
+					"type": "host",
+					"value": []interface{}{
+						map[string]interface{}{"hostName": "a.example.com", "port": "22"},
+						map[string]interface{}{"hostName": "b.example.com", "port": "2222"},
+					},
+				},
+				map[string]interface{}{
+					"type": "name",
+					"value": []interface{}{
+						map[string]interface{}{"first": "John"},
+						map[string]interface{}{"first": "Jane"},
+					},
+				},
+			},
+		},
+	}
+
+	host := getFieldResourceData("host", "fields", record).([]interface{})
+	values := host[0].(map[string]interface{})["value"].([]interface{})
+	if len(values) != 2 {
+		t.Fatalf("expected 2 host values, got %d", len(values))
+	}
+	if values[1].(map[string]interface{})["host_name"] != "b.example.com" {
+		t.Errorf("host values out of order: %v", values)
+	}
+
+	name := getFieldResourceData("name", "fields", record).([]interface{})
+	if values := name[0].(map[string]interface{})["value"].([]interface{}); len(values) != 1 {
+		t.Errorf("expected single name value, got %d", len(values))
+	}
+}
+
+func TestGetPhoneItemDataAllValues(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "contact",
+			"fields": []interface{}{
+				map[string]interface{}{
+					"type": "phone",
+					"value": []interface{}{
+						map[string]interface{}{"number": "555-0001", "type": "Work"},
+						map[string]interface{}{"number": "555-0002", "type": "Mobile"},
+					},
+				},
+			},
+		},
+	}
+
+	items := getPhoneItemData(record)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 phones, got %d", len(items))
+	}
+	if items[1].(map[string]interface{})["number"] != "555-0002" {
+		t.Errorf("phone items out of order: %v", items)
+	}
+
+	list, diags := phoneToListValue(context.Background(), record)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	elements := list.Elements()
+	if len(elements) != 2 {
+		t.Fatalf("expected 2 ephemeral phones, got %d", len(elements))
+	}
+	number := elements[1].(types.Object).Attributes()["number"].(types.String).ValueString()
+	if number != "555-0002" {
+		t.Errorf("ephemeral phone[1] = %q, want %q", number, "555-0002")
+	}
+}
+
+func TestSuppressEquivalentJSONItems(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType string
+		old       string
+		new       string
+		want      bool
+	}{
+		{"identical", "phone", `{"number":"1"}`, `{"number":"1"}`, true},
+		{"object_vs_single_array", "phone", `{"number":"1"}`, `[{"number":"1"}]`, true},
+		{"key_order", "address", `{"a":"1","b":"2"}`, `{"b":"2","a":"1"}`, true},
+		{"lowercase_type", "paymentcard", `{"a":"1"}`, `[{"a":"1"}]`, true},
+		{"different_values", "phone", `{"number":"1"}`, `[{"number":"2"}]`, false},
+		{"different_order", "phone", `[{"n":"1"},{"n":"2"}]`, `[{"n":"2"},{"n":"1"}]`, false},
+		{"different_length", "phone", `{"n":"1"}`, `[{"n":"1"},{"n":"2"}]`, false},
+		{"non_object_items", "phone", `1`, `[1]`, false},
+		{"empty_new", "phone", `{"n":"1"}`, ``, false},
+		{"plain_text", "text", `abc`, `abd`, false},
+		{"text_number_format", "text", `1`, `1.0`, false},
+		{"text_exponent", "text", `1e3`, `1000`, false},
+		{"secret_whitespace", "secret", `true`, ` true`, false},
+		{"password_array", "password", `1`, `[1]`, false},
+		{"text_json_object", "text", `{"a":"1"}`, `[{"a":"1"}]`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"custom": schemaCustomField()}, map[string]interface{}{
+				"custom": []interface{}{map[string]interface{}{"type": tt.fieldType, "label": "field", "value": tt.new}},
+			})
+			if got := suppressEquivalentJSONItems("custom.0.value", tt.old, tt.new, d); got != tt.want {
+				t.Errorf("suppressEquivalentJSONItems(%q, %q) of %s = %v, want %v", tt.old, tt.new, tt.fieldType, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetFieldsResourceDataMatchesLabels verifies that repeatable field blocks are
+// matched by (type,label) on read, unlabeled blocks fall back to position and
+// unmanaged fields of the same type are appended in record order.
+func TestGetFieldsResourceDataMatchesLabels(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "login",
+			"fields": []interface{}{
+				map[string]interface{}{"type": "url", "label": "Admin", "value": []interface{}{"https://admin"}},
+				map[string]interface{}{"type": "login", "value": []interface{}{"user"}},
+				map[string]interface{}{"type": "url", "label": "Portal", "value": []interface{}{"https://portal"}},
+				map[string]interface{}{"type": "url", "value": []interface{}{"https://other"}},
+			},
+		},
+	}
+
+	configured := []interface{}{
+		map[string]interface{}{"label": "Portal"},
+		map[string]interface{}{"label": ""},
+	}
+	items := getFieldsResourceData("url", "fields", record, configured)
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.(map[string]interface{})["value"].(string))
+	}
+	want := []string{"https://portal", "https://admin", "https://other"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// import - nothing configured, all fields in record order
+	items = getFieldsResourceData("url", "fields", record, nil)
+	if len(items) != 3 || items[0].(map[string]interface{})["label"] != "Admin" {
+		t.Errorf("unexpected import result: %v", items)
+	}
+}
+
+func TestNewFieldsFromSchemaDuplicateLabels(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"label": "Site", "value": "https://a"},
+		map[string]interface{}{"label": "Site", "value": "https://b"},
+	}
+	if _, err := NewFieldsFromSchema("url", data); err == nil {
+		t.Error("expected error for duplicate labels, got nil")
+	}
+
+	data[1].(map[string]interface{})["label"] = "Other"
+	fields, err := NewFieldsFromSchema("url", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fields) != 2 {
+		t.Errorf("expected 2 fields, got %d", len(fields))
+	}
+}
+
+// TestApplyFieldListChange verifies that repeatable blocks are written by position
+// while fields of other types keep their place in the record.
+func TestApplyFieldListChange(t *testing.T) {
+	newRecord := func() *core.Record {
+		return &core.Record{
+			RecordDict: map[string]interface{}{
+				"type": "login",
+				"fields": []interface{}{
+					map[string]interface{}{"type": "login", "value": []interface{}{"user"}},
+					map[string]interface{}{"type": "url", "label": "A", "value": []interface{}{"https://a"}},
+					map[string]interface{}{"type": "password", "value": []interface{}{"secret"}},
+					map[string]interface{}{"type": "url", "label": "B", "value": []interface{}{"https://b"}},
+					map[string]interface{}{"type": "url", "label": "C", "value": []interface{}{"https://c"}},
+				},
+			},
+		}
+	}
+	fieldTypes := func(r *core.Record) []string {
+		result := []string{}
+		for _, f := range r.RecordDict["fields"].([]interface{}) {
+			result = append(result, f.(map[string]interface{})["type"].(string))
+		}
+		return result
+	}
+
+	record := newRecord()
+	data := []interface{}{
+		map[string]interface{}{"label": "A", "value": "https://a2"},
+		map[string]interface{}{"label": "B", "value": "https://b2"},
+	}
+	if _, err := applyFieldListChange("fields", "url", data, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fieldTypes(record); strings.Join(got, ",") != "login,url,password,url" {
+		t.Errorf("unexpected field layout after shrink: %v", got)
+	}
+	if v := record.RecordDict["fields"].([]interface{})[3].(map[string]interface{})["value"].([]interface{})[0]; v != "https://b2" {
+		t.Errorf("second url = %v, want https://b2", v)
+	}
+
+	record = newRecord()
+	data = append(data,
+		map[string]interface{}{"label": "C", "value": "https://c2"},
+		map[string]interface{}{"label": "D", "value": "https://d"})
+	if _, err := applyFieldListChange("fields", "url", data, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fieldTypes(record); strings.Join(got, ",") != "login,url,password,url,url,url" {
+		t.Errorf("unexpected field layout after grow: %v", got)
+	}
+}
+
+// TestApplyFieldChangeMatchesLabel verifies that single-value attributes sharing a
+// field type (ex. several labeled text fields on pam_machine) update only the field
+// with the matching label instead of the first field of that type.
+func TestApplyFieldChangeMatchesLabel(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "pamMachine",
+			"fields": []interface{}{
+				map[string]interface{}{"type": "text", "label": "Operating System", "value": []interface{}{"linux"}},
+				map[string]interface{}{"type": "text", "label": "Instance Name", "value": []interface{}{"old"}},
+			},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourcePamMachine().Schema, map[string]interface{}{
+		"instance_name": []interface{}{
+			map[string]interface{}{"label": "Instance Name", "value": "new"},
+		},
+	})
+	if _, err := ApplyFieldChange("fields", "instance_name", d, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := record.RecordDict["fields"].([]interface{})
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+	if v := fields[0].(map[string]interface{})["value"].([]interface{})[0]; v != "linux" {
+		t.Errorf("operating system was overwritten: %v", v)
+	}
+	if v := fields[1].(map[string]interface{})["value"].([]interface{})[0]; v != "new" {
+		t.Errorf("instance name = %v, want new", v)
+	}
+}
+
+// TestGetLabeledFieldResourceData verifies that single-value text attributes read
+// the field with the configured label instead of the first text field.
+func TestGetLabeledFieldResourceData(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "contact",
+			"fields": []interface{}{
+				map[string]interface{}{"type": "text", "label": "Department", "value": []interface{}{"IT"}},
+				map[string]interface{}{"type": "text", "label": "Company", "value": []interface{}{"Keeper"}},
+			},
+		},
+	}
+
+	configured := []interface{}{map[string]interface{}{"label": "Company"}}
+	company := getLabeledFieldResourceData("text", "fields", record, configured).([]interface{})
+	if len(company) != 1 || company[0].(map[string]interface{})["value"] != "Keeper" {
+		t.Errorf("labeled: got %v, want the Company field", company)
+	}
+
+	// import - nothing configured, the first text field
+	company = getLabeledFieldResourceData("text", "fields", record, nil).([]interface{})
+	if len(company) != 1 || company[0].(map[string]interface{})["value"] != "IT" {
+		t.Errorf("unlabeled: got %v, want the first text field", company)
+	}
+}
+
+// TestGetFieldsResourceDataDuplicateLabels verifies that a record field sharing the
+// label of a configured block is not dropped but shown as drift.
+func TestGetFieldsResourceDataDuplicateLabels(t *testing.T) {
+	record := &core.Record{
+		RecordDict: map[string]interface{}{
+			"type": "login",
+			"fields": []interface{}{
+				map[string]interface{}{"type": "url", "label": "Site", "value": []interface{}{"https://a"}},
+				map[string]interface{}{"type": "url", "label": "Site", "value": []interface{}{"https://b"}},
+			},
+		},
+	}
+
+	configured := []interface{}{map[string]interface{}{"label": "Site"}}
+	items := getFieldsResourceData("url", "fields", record, configured)
+	if len(items) != 2 || items[0].(map[string]interface{})["value"] != "https://a" || items[1].(map[string]interface{})["value"] != "https://b" {
+		t.Errorf("got %v, want both Site fields in record order", items)
+	}
+}
@@ -0,0 +1,110 @@
+package secretsmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Notation results of secretsmanager_field - the same attributes in both
+// providers.
+const (
+	fieldValueDescription = "The value selected by the notation. Complex values (ex. `name`, `phone`) and multiple values " +
+		"(ex. `[]` index) are JSON-encoded, file attachments are returned as text."
+	fieldValuesDescription    = "The values selected by the notation - complex values JSON-encoded."
+	fieldValueJsonDescription = "The value selected by the notation JSON-encoded - an array when the notation selects multiple values."
+)
+
+// notationMatchError is returned when a valid notation selects nothing - no
+// record, field, file, value index or property matches.
+type notationMatchError struct {
+	Notation string
+	Reason   string
+}
+
+func (e *notationMatchError) Error() string {
+	return fmt.Sprintf("notation '%s' doesn't match: %s", e.Notation, e.Reason)
+}
+
+// notationMatchReasons are the KSM SDK errors of notations selecting nothing.
+var notationMatchReasons = []string{
+	"no records match",
+	"has no file attachments",
+	"has no files matching",
+	"has no fields matching",
+	"index out of bounds",
+	"cannot find the dictionary key",
+}
+
+// notationError converts the KSM SDK error of a notation selecting nothing to
+// a notationMatchError - other errors are returned as is.
+func notationError(notation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, reason := range notationMatchReasons {
+		if strings.Contains(err.Error(), reason) {
+			return &notationMatchError{Notation: notation, Reason: err.Error()}
+		}
+	}
+	return err
+}
+
+// notationResult is the notation result as the value, values and value_json
+// attributes of secretsmanager_field.
+type notationResult struct {
+	Value     string
+	Values    []string
+	ValueJson string
+}
+
+// newNotationResult converts the values returned by GetNotation - strings as
+// is, file content as text and complex values JSON-encoded.
+func newNotationResult(values []interface{}) (notationResult, error) {
+	result := notationResult{Values: make([]string, 0, len(values))}
+	plain := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if content, ok := value.([]byte); ok {
+			if !utf8.Valid(content) {
+				return result, fmt.Errorf("the file attachment isn't text - use the secretsmanager_file data source for binary files")
+			}
+			value = string(content)
+		}
+		plain = append(plain, value)
+
+		switch v := value.(type) {
+		case nil:
+			result.Values = append(result.Values, "")
+		case string:
+			result.Values = append(result.Values, v)
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return result, fmt.Errorf("failed to encode the field value: %w", err)
+			}
+			result.Values = append(result.Values, string(encoded))
+		}
+	}
+
+	var encoded []byte
+	var err error
+	if len(plain) == 1 {
+		encoded, err = json.Marshal(plain[0])
+	} else {
+		encoded, err = json.Marshal(plain)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to encode the field value: %w", err)
+	}
+	result.ValueJson = string(encoded)
+
+	switch len(result.Values) {
+	case 0:
+	case 1:
+		result.Value = result.Values[0]
+	default:
+		result.Value = result.ValueJson
+	}
+	return result, nil
+}
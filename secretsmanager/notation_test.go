@@ -0,0 +1,128 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceFieldNotation(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":  "login",
+		"title": "web",
+		"fields": []interface{}{
+			map[string]interface{}{"type": "login", "value": []interface{}{"admin"}},
+			map[string]interface{}{"type": "name", "value": []interface{}{map[string]interface{}{"first": "John", "last": "Smith"}}},
+		},
+		"custom": []interface{}{
+			map[string]interface{}{"type": "phone", "label": "Phones", "value": []interface{}{
+				map[string]interface{}{"number": "555-1234"},
+				map[string]interface{}{"number": "555-5678"},
+			}},
+		},
+	})
+	vault.AddFile(uid, "cert.pem", []byte("-----BEGIN CERTIFICATE-----"))
+
+	ds := Provider().DataSourcesMap["secretsmanager_field"]
+	read := func(path string) *schema.ResourceData {
+		t.Helper()
+		d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"path": path})
+		if diags := ds.ReadContext(ctx, d, providerMeta{client: vault}); diags.HasError() {
+			t.Fatalf("read %s: %v", path, diags)
+		}
+		return d
+	}
+
+	for _, tc := range []struct {
+		path      string
+		value     string
+		valueJson string
+		values    int
+	}{
+		{"/field/login", "admin", `"admin"`, 1},
+		{"/field/name", `{"first":"John","last":"Smith"}`, `{"first":"John","last":"Smith"}`, 1},
+		{"/field/name[0][first]", "John", `"John"`, 1},
+		{"/custom_field/Phones[1][number]", "555-5678", `"555-5678"`, 1},
+		{"/custom_field/Phones[]", `[{"number":"555-1234"},{"number":"555-5678"}]`, `[{"number":"555-1234"},{"number":"555-5678"}]`, 2},
+		{"/file/cert.pem", "-----BEGIN CERTIFICATE-----", `"-----BEGIN CERTIFICATE-----"`, 1},
+	} {
+		d := read(uid + tc.path)
+		if got := d.Get("value").(string); got != tc.value {
+			t.Errorf("%s: value = %s, want %s", tc.path, got, tc.value)
+		}
+		if got := d.Get("value_json").(string); got != tc.valueJson {
+			t.Errorf("%s: value_json = %s, want %s", tc.path, got, tc.valueJson)
+		}
+		if got := d.Get("values").([]interface{}); len(got) != tc.values {
+			t.Errorf("%s: values = %v, want %d values", tc.path, got, tc.values)
+		}
+	}
+	if got := read(uid + "/custom_field/Phones[]").Get("values.1").(string); got != `{"number":"555-5678"}` {
+		t.Errorf("values.1 = %s", got)
+	}
+
+	for _, path := range []string{
+		uid + "/field/password",
+		uid + "/custom_field/Phones[2]",
+		uid + "/field/name[0][middle]",
+		uid + "/file/missing.pem",
+		"missing/field/login",
+	} {
+		_, _, err := readNotation(ctx, vault, path)
+		var matchErr *notationMatchError
+		if !errors.As(err, &matchErr) || matchErr.Notation != path {
+			t.Errorf("%s: got error %v, want notationMatchError", path, err)
+		}
+	}
+	if _, _, err := readNotation(ctx, vault, uid+"/field/login[x]"); err == nil || errors.As(err, new(*notationMatchError)) {
+		t.Errorf("invalid index: got error %v, want a notation syntax error", err)
+	}
+}
+
+func TestEphemeralFieldNotation(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":  "login",
+		"title": "web",
+		"custom": []interface{}{
+			map[string]interface{}{"type": "text", "label": "Hosts", "value": []interface{}{"a.example.com", "b.example.com"}},
+		},
+	})
+	e := &ephemeralField{meta: providerMeta{client: vault}}
+
+	resp := openTestEphemeral(t, e, map[string]interface{}{"path": uid + "/custom_field/Hosts[]"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	var data ephemeralFieldModel
+	resp.Diagnostics.Append(resp.Result.Get(ctx, &data)...)
+	var values []string
+	resp.Diagnostics.Append(data.Values.ElementsAs(ctx, &values, false)...)
+	if len(values) != 2 || values[1] != "b.example.com" || data.ValueJson.ValueString() != `["a.example.com","b.example.com"]` {
+		t.Errorf("values = %v, value_json = %s", values, data.ValueJson)
+	}
+
+	resp = openTestEphemeral(t, e, map[string]interface{}{"path": uid + "/custom_field/Missing"})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a field that doesn't match")
+	}
+}
+
+func TestNewNotationResult(t *testing.T) {
+	result, err := newNotationResult([]interface{}{})
+	if err != nil || result.Value != "" || result.ValueJson != "[]" || len(result.Values) != 0 {
+		t.Errorf("empty result = %+v, %v", result, err)
+	}
+	result, err = newNotationResult([]interface{}{"a", float64(5), true})
+	if err != nil || result.Value != `["a",5,true]` || result.Values[1] != "5" || result.Values[2] != "true" {
+		t.Errorf("multiple values = %+v, %v", result, err)
+	}
+	if _, err = newNotationResult([]interface{}{[]byte{0xff, 0xfe}}); err == nil {
+		t.Error("binary file content: expected an error")
+	}
+}
@@ -0,0 +1,246 @@
+package secretsmanager
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// defaultPasswordLength is the length of generated passwords without a
+// complexity length - the same for password fields and secretsmanager_password.
+const defaultPasswordLength = 16
+
+// ambiguousCharacters are left out of passwords with exclude_ambiguous - they
+// are easily mistaken for each other when read or typed.
+const ambiguousCharacters = "0Oo1lI|"
+
+const (
+	defaultPassphraseWords     = 5
+	minPassphraseWords         = 4
+	defaultPassphraseSeparator = "-"
+)
+
+// passphraseWordlist is the BIP-39 English wordlist - 2048 short, common and
+// distinct words, 11 bits of entropy per word.
+//
+//go:embed wordlist/english.txt
+var passphraseWordlist string
+
+var passphraseWords = strings.Fields(passphraseWordlist)
+
+// passwordOptions are the complexity options of a generated password.
+type passwordOptions struct {
+	Length int
+	// Caps, Lowercase, Digits and Special count the characters of each class -
+	// positive for a minimum, 0 or negative for an exact count and nil to
+	// leave the class to the generator.
+	Caps      *int
+	Lowercase *int
+	Digits    *int
+	Special   *int
+	// SpecialCharacters replaces the default set of special characters
+	SpecialCharacters string
+	// ExcludeCharacters are never used - letters and digits are replaced with
+	// others of the same class
+	ExcludeCharacters   string
+	ExcludeAmbiguous    bool
+	MustStartWithLetter bool
+	// Passphrase generates words instead - the other options are ignored
+	Passphrase *passphraseOptions
+}
+
+// passphraseOptions are the options of a generated passphrase.
+type passphraseOptions struct {
+	Words      int
+	Separator  string
+	Capitalize bool
+	// MinLength adds words until the passphrase is long enough
+	MinLength int
+}
+
+// generatePassword generates a password with core.GeneratePassword.
+func generatePassword(options passwordOptions) (string, error) {
+	if options.Passphrase != nil {
+		return generatePassphrase(*options.Passphrase)
+	}
+	if options.Length == 0 {
+		options.Length = defaultPasswordLength
+	}
+	count := func(n *int) int {
+		if n == nil {
+			return 0
+		}
+		return *n
+	}
+	if err := validateComplexity(options.Length, count(options.Caps), count(options.Lowercase), count(options.Digits), count(options.Special)); err != nil {
+		return "", err
+	}
+
+	excluded := options.ExcludeCharacters
+	if options.ExcludeAmbiguous {
+		excluded += ambiguousCharacters
+	}
+	specialCharacters := options.SpecialCharacters
+	if specialCharacters == "" {
+		specialCharacters = core.AsciiSpecialCharacters
+	}
+	if specialCharacters = withoutCharacters(specialCharacters, excluded); specialCharacters == "" {
+		return "", errors.New("the special character set has no characters left to generate from")
+	}
+	// a class with every character excluded can't be generated at all
+	for _, class := range []struct {
+		name       string
+		characters string
+		count      **int
+	}{
+		{"lowercase", core.AsciiLowercase, &options.Lowercase},
+		{"caps", core.AsciiUppercase, &options.Caps},
+		{"digits", core.AsciiDigits, &options.Digits},
+	} {
+		if withoutCharacters(class.characters, excluded) != "" {
+			continue
+		}
+		if count(*class.count) != 0 {
+			return "", fmt.Errorf("%s = %d but every character of the class is excluded", class.name, count(*class.count))
+		}
+		none := 0
+		*class.count = &none
+	}
+
+	password, err := core.GeneratePassword(options.Length,
+		passwordCount(options.Lowercase),
+		passwordCount(options.Caps),
+		passwordCount(options.Digits),
+		passwordCount(options.Special),
+		specialCharacters)
+	if err != nil {
+		return "", err
+	}
+	result := []rune(password)
+	if err := replaceExcluded(result, excluded); err != nil {
+		return "", err
+	}
+	if options.MustStartWithLetter {
+		if err := startWithLetter(result); err != nil {
+			return "", err
+		}
+	}
+	return string(result), nil
+}
+
+// generatePassphrase joins random words of the embedded wordlist.
+func generatePassphrase(options passphraseOptions) (string, error) {
+	if options.Words == 0 {
+		options.Words = defaultPassphraseWords
+	}
+	if options.Words < minPassphraseWords {
+		return "", fmt.Errorf("expected at least %d passphrase words, got %d", minPassphraseWords, options.Words)
+	}
+	words := make([]string, 0, options.Words)
+	length := 0
+	for len(words) < options.Words || length < options.MinLength {
+		n, err := randomIndex(len(passphraseWords))
+		if err != nil {
+			return "", err
+		}
+		word := passphraseWords[n]
+		if options.Capitalize {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		if len(words) > 0 {
+			length += len(options.Separator)
+		}
+		length += len(word)
+		words = append(words, word)
+	}
+	return strings.Join(words, options.Separator), nil
+}
+
+// passwordCount is the core.GeneratePassword count of a character class.
+func passwordCount(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func withoutCharacters(characters, excluded string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+		return r
+	}, characters)
+}
+
+// replaceExcluded swaps the excluded letters and digits of the password for
+// random ones of the same class, so the counts of each class are kept. Only
+// when the whole class is excluded (all counts exact zero) they are swapped
+// for any letter or digit left.
+func replaceExcluded(password []rune, excluded string) error {
+	if excluded == "" {
+		return nil
+	}
+	alphanumeric := withoutCharacters(core.AsciiLowercase+core.AsciiUppercase+core.AsciiDigits, excluded)
+	for i, r := range password {
+		if !strings.ContainsRune(excluded, r) {
+			continue
+		}
+		for _, class := range []string{core.AsciiLowercase, core.AsciiUppercase, core.AsciiDigits} {
+			if !strings.ContainsRune(class, r) {
+				continue
+			}
+			replacements := withoutCharacters(class, excluded)
+			if replacements == "" {
+				replacements = alphanumeric
+			}
+			if replacements == "" {
+				return errors.New("every letter and digit is excluded")
+			}
+			n, err := randomIndex(len(replacements))
+			if err != nil {
+				return err
+			}
+			password[i] = rune(replacements[n])
+		}
+	}
+	return nil
+}
+
+// startWithLetter swaps the first character of the password with a random
+// letter of the password.
+func startWithLetter(password []rune) error {
+	letters := []int{}
+	for i, r := range password {
+		if unicode.IsLetter(r) {
+			letters = append(letters, i)
+		}
+	}
+	if len(letters) == 0 {
+		return errors.New("must_start_with_letter needs at least one letter in the password")
+	}
+	if unicode.IsLetter(password[0]) {
+		return nil
+	}
+	n, err := randomIndex(len(letters))
+	if err != nil {
+		return err
+	}
+	password[0], password[letters[n]] = password[letters[n]], password[0]
+	return nil
+}
+
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
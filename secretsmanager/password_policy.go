@@ -0,0 +1,302 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Provider password policy settings - the same block in both providers.
+const (
+	passwordPolicyDescription = "Password policy of every password field of managed resources and of `secretsmanager_password`. " +
+		"Generated passwords use the policy minimums as defaults and the plan fails when a `complexity` block or an explicit value " +
+		"is weaker than the policy. Passphrases are checked against `min_length` and `forbidden_characters` only."
+	minLengthDescription           = "Minimum password length."
+	minCapsDescription             = "Minimum number of uppercase characters."
+	minLowercaseDescription        = "Minimum number of lowercase characters."
+	minDigitsDescription           = "Minimum number of digits."
+	minSpecialDescription          = "Minimum number of special characters."
+	forbiddenCharactersDescription = "Characters no password may contain - they are left out of generated passwords."
+)
+
+// passwordPolicy is the provider wide minimum complexity of password fields
+// so a module can't generate or set weak passwords.
+type passwordPolicy struct {
+	MinLength           int
+	MinCaps             int
+	MinLowercase        int
+	MinDigits           int
+	MinSpecial          int
+	ForbiddenCharacters string
+}
+
+// newPasswordPolicy returns the policy, nil when it has no requirements.
+func newPasswordPolicy(policy passwordPolicy) (*passwordPolicy, error) {
+	for name, n := range map[string]int{
+		"min_length":    policy.MinLength,
+		"min_caps":      policy.MinCaps,
+		"min_lowercase": policy.MinLowercase,
+		"min_digits":    policy.MinDigits,
+		"min_special":   policy.MinSpecial,
+	} {
+		if n < 0 {
+			return nil, fmt.Errorf("password_policy %s = %d - expected 0 or more", name, n)
+		}
+	}
+	if policy == (passwordPolicy{}) {
+		return nil, nil
+	}
+	return &policy, nil
+}
+
+// passwordPolicyFromSchema reads the password_policy block of the provider
+// configuration.
+func passwordPolicyFromSchema(data interface{}) (*passwordPolicy, error) {
+	block := firstSchemaBlock(data)
+	if block == nil {
+		return nil, nil
+	}
+	policy := passwordPolicy{}
+	policy.MinLength, _ = block["min_length"].(int)
+	policy.MinCaps, _ = block["min_caps"].(int)
+	policy.MinLowercase, _ = block["min_lowercase"].(int)
+	policy.MinDigits, _ = block["min_digits"].(int)
+	policy.MinSpecial, _ = block["min_special"].(int)
+	policy.ForbiddenCharacters, _ = block["forbidden_characters"].(string)
+	return newPasswordPolicy(policy)
+}
+
+// applyDefaults raises the options of a generated password to the policy -
+// unset counts and length default to the policy minimums and forbidden
+// characters are excluded.
+func (p *passwordPolicy) applyDefaults(options passwordOptions) passwordOptions {
+	if p == nil {
+		return options
+	}
+	options.ExcludeCharacters += p.ForbiddenCharacters
+	if options.Passphrase != nil {
+		passphrase := *options.Passphrase
+		passphrase.MinLength = max(passphrase.MinLength, p.MinLength)
+		options.Passphrase = &passphrase
+		return options
+	}
+	if options.Length == 0 {
+		options.Length = defaultPasswordLength
+	}
+	options.Length = max(options.Length, p.MinLength)
+	atLeast := func(n *int, minimum int) *int {
+		if minimum == 0 {
+			return n
+		}
+		if n == nil || *n >= 0 && *n < minimum {
+			return &minimum
+		}
+		if *n < 0 && -*n < minimum {
+			exact := -minimum
+			return &exact
+		}
+		return n
+	}
+	options.Caps = atLeast(options.Caps, p.MinCaps)
+	options.Lowercase = atLeast(options.Lowercase, p.MinLowercase)
+	options.Digits = atLeast(options.Digits, p.MinDigits)
+	options.Special = atLeast(options.Special, p.MinSpecial)
+	return options
+}
+
+// checkOptions returns an error when the options of a generated password set
+// below the policy - path is the attribute path of the complexity options.
+func (p *passwordPolicy) checkOptions(path string, options passwordOptions) error {
+	if p == nil {
+		return nil
+	}
+	if options.Passphrase != nil {
+		if options.Passphrase.Separator != "" && strings.ContainsAny(options.Passphrase.Separator, p.ForbiddenCharacters) {
+			return fmt.Errorf("%s.passphrase separator %q has characters forbidden by password_policy", path, options.Passphrase.Separator)
+		}
+		return nil
+	}
+	abs := func(n *int) int {
+		if n == nil {
+			return 0
+		}
+		return max(*n, -*n)
+	}
+	length := max(options.Length, abs(options.Caps)+abs(options.Lowercase)+abs(options.Digits)+abs(options.Special))
+	if options.Length != 0 && length < p.MinLength {
+		return fmt.Errorf("%s.length = %d is below password_policy min_length = %d", path, options.Length, p.MinLength)
+	}
+	for _, class := range []struct {
+		name    string
+		count   *int
+		minimum int
+	}{
+		{"caps", options.Caps, p.MinCaps},
+		{"lowercase", options.Lowercase, p.MinLowercase},
+		{"digits", options.Digits, p.MinDigits},
+		{"special", options.Special, p.MinSpecial},
+	} {
+		// 0 leaves the class to the policy
+		if n := abs(class.count); n != 0 && n < class.minimum {
+			return fmt.Errorf("%s.%s = %d is below password_policy min_%s = %d", path, class.name, *class.count, class.name, class.minimum)
+		}
+	}
+	if options.SpecialCharacters != "" && withoutCharacters(options.SpecialCharacters, p.ForbiddenCharacters) == "" {
+		return fmt.Errorf("%s.special_characters has only characters forbidden by password_policy", path)
+	}
+	return nil
+}
+
+// checkValue returns an error when the explicit password value doesn't meet
+// the policy - the value itself is never part of the error.
+func (p *passwordPolicy) checkValue(path, value string) error {
+	if p == nil {
+		return nil
+	}
+	if n := len([]rune(value)); n < p.MinLength {
+		return fmt.Errorf("%s is %d characters long - password_policy min_length = %d", path, n, p.MinLength)
+	}
+	for _, class := range []struct {
+		name    string
+		match   func(rune) bool
+		minimum int
+	}{
+		{"caps", unicode.IsUpper, p.MinCaps},
+		{"lowercase", unicode.IsLower, p.MinLowercase},
+		{"digits", unicode.IsDigit, p.MinDigits},
+		{"special", func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }, p.MinSpecial},
+	} {
+		n := 0
+		for _, r := range value {
+			if class.match(r) {
+				n++
+			}
+		}
+		if n < class.minimum {
+			return fmt.Errorf("%s has %d %s characters - password_policy min_%s = %d", path, n, class.name, class.name, class.minimum)
+		}
+	}
+	if p.ForbiddenCharacters != "" && strings.ContainsAny(value, p.ForbiddenCharacters) {
+		return fmt.Errorf("%s has characters forbidden by password_policy", path)
+	}
+	return nil
+}
+
+// passwordFieldNames are the attributes of the resource schema holding a
+// password field - the ones with a complexity block.
+func passwordFieldNames(r *schema.Resource) []string {
+	names := []string{}
+	for name, s := range r.Schema {
+		if elem, ok := s.Elem.(*schema.Resource); ok {
+			if _, found := elem.Schema["complexity"]; found {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkPasswordField checks the password field block of the plan - complexity
+// of generated passwords, the value otherwise.
+func (p *passwordPolicy) checkPasswordField(d *schema.ResourceDiff, name string) error {
+	block := firstSchemaBlock(d.Get(name))
+	if block == nil {
+		return nil
+	}
+	path := name + ".0"
+	if generate, _ := block["generate"].(string); generate != "" {
+		return p.checkOptions(path+".complexity.0", complexityOptions(block))
+	}
+	return p.checkKnownValue(d, path+".value")
+}
+
+// checkKnownValue checks the string value of the plan - values only known at
+// apply time can't be checked.
+func (p *passwordPolicy) checkKnownValue(d *schema.ResourceDiff, key string) error {
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+	if value, _ := d.Get(key).(string); value != "" {
+		return p.checkValue(key, value)
+	}
+	return nil
+}
+
+// complexityOptions are the generation options of the password field block
+// with the counts of its complexity block.
+func complexityOptions(block map[string]interface{}) passwordOptions {
+	options := parsePasswordOptions([]interface{}{block})
+	complexity := firstSchemaBlock(block["complexity"])
+	count := func(key string) *int {
+		if n, ok := complexity[key].(int); ok {
+			return &n
+		}
+		return nil
+	}
+	options.Length, _ = complexity["length"].(int)
+	options.Caps = count("caps")
+	options.Lowercase = count("lowercase")
+	options.Digits = count("digits")
+	options.Special = count("special")
+	return options
+}
+
+// passwordPolicyCustomizeDiff fails the plan when a changed password field or
+// custom password field of the resource r doesn't meet the password policy
+// of the provider.
+func passwordPolicyCustomizeDiff(r *schema.Resource, next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	fields := passwordFieldNames(r)
+	_, hasCustom := r.Schema["custom"]
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if provider, ok := m.(providerMeta); ok && provider.passwordPolicy != nil {
+			for _, name := range fields {
+				if d.Id() != "" && !d.HasChange(name) {
+					continue
+				}
+				if err := provider.passwordPolicy.checkPasswordField(d, name); err != nil {
+					return err
+				}
+			}
+			if hasCustom {
+				if err := provider.passwordPolicy.checkCustomFields(d); err != nil {
+					return err
+				}
+			}
+		}
+		if next != nil {
+			return next(ctx, d, m)
+		}
+		return nil
+	}
+}
+
+// checkCustomFields checks the new and changed values of custom password fields.
+func (p *passwordPolicy) checkCustomFields(d *schema.ResourceDiff) error {
+	if !d.HasChange("custom") {
+		return nil
+	}
+	oldData, newData := d.GetChange("custom")
+	oldItems, _ := oldData.([]interface{})
+	newItems, _ := newData.([]interface{})
+	for i, item := range newItems {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fieldType, _ := m["type"].(string); !strings.EqualFold(strings.TrimSpace(fieldType), "password") {
+			continue
+		}
+		if i < len(oldItems) && fmt.Sprint(oldItems[i]) == fmt.Sprint(item) {
+			continue
+		}
+		if err := p.checkKnownValue(d, fmt.Sprintf("custom.%d.value", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
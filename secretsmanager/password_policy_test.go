@@ -0,0 +1,156 @@
+package secretsmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func TestPasswordPolicyFromSchema(t *testing.T) {
+	if policy, err := passwordPolicyFromSchema([]interface{}{}); policy != nil || err != nil {
+		t.Errorf("no block: %v, %v", policy, err)
+	}
+	if policy, err := passwordPolicyFromSchema([]interface{}{map[string]interface{}{"min_length": 0, "forbidden_characters": ""}}); policy != nil || err != nil {
+		t.Errorf("empty block: %v, %v", policy, err)
+	}
+	if _, err := passwordPolicyFromSchema([]interface{}{map[string]interface{}{"min_digits": -1}}); err == nil {
+		t.Error("expected an error for a negative minimum")
+	}
+	policy, err := passwordPolicyFromSchema([]interface{}{map[string]interface{}{"min_length": 20, "forbidden_characters": "'\""}})
+	if err != nil || policy == nil || policy.MinLength != 20 || policy.ForbiddenCharacters != "'\"" {
+		t.Errorf("policy = %+v, %v", policy, err)
+	}
+}
+
+func TestPasswordPolicyDefaults(t *testing.T) {
+	policy := &passwordPolicy{MinLength: 24, MinDigits: 4, MinSpecial: 3, ForbiddenCharacters: "$%&"}
+	fieldData := []interface{}{map[string]interface{}{"generate": "true"}}
+	for i := 0; i < 20; i++ {
+		field := &core.Password{}
+		if _, err := applyGeneratePassword(fieldData, field, policy); err != nil {
+			t.Fatalf("applyGeneratePassword: %v", err)
+		}
+		password := field.Value[0]
+		if len(password) < 24 || countRunes(password, unicode.IsDigit) < 4 || strings.ContainsAny(password, policy.ForbiddenCharacters) {
+			t.Fatalf("password %q doesn't meet the policy", password)
+		}
+		if err := policy.checkValue("password", password); err != nil {
+			t.Fatalf("generated password: %v", err)
+		}
+		// the record keeps the configured complexity
+		if field.Complexity != nil {
+			t.Fatalf("complexity = %+v, want none", field.Complexity)
+		}
+	}
+
+	passphrase, err := generatePassword(policy.applyDefaults(passwordOptions{Passphrase: &passphraseOptions{Words: 4}}))
+	if err != nil {
+		t.Fatalf("generatePassword: %v", err)
+	}
+	if len(passphrase) < 24 {
+		t.Errorf("passphrase %q is shorter than min_length", passphrase)
+	}
+}
+
+func TestPasswordPolicyCheckValue(t *testing.T) {
+	policy := &passwordPolicy{MinLength: 12, MinCaps: 1, MinDigits: 2, MinSpecial: 1, ForbiddenCharacters: "'"}
+	for value, valid := range map[string]bool{
+		"Abcdefgh12#x":  true,
+		"Abcdefgh12#":   false,
+		"abcdefgh12#xy": false,
+		"Abcdefgh1#xyz": false,
+		"Abcdefgh12xyz": false,
+		"Abcdefgh12'xy": false,
+	} {
+		err := policy.checkValue("password.0.value", value)
+		if (err == nil) != valid {
+			t.Errorf("checkValue(%q) = %v, want valid = %v", value, err, valid)
+		}
+		if err != nil && strings.Contains(err.Error(), value) {
+			t.Errorf("error %q leaks the value", err)
+		}
+	}
+}
+
+func TestPasswordPolicyCustomizeDiff(t *testing.T) {
+	ctx := context.Background()
+	meta := providerMeta{client: newFakeVault(), passwordPolicy: &passwordPolicy{MinLength: 16, MinDigits: 2}}
+	r := Provider().ResourcesMap["secretsmanager_login"]
+
+	plan := func(config map[string]interface{}) error {
+		_, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), meta)
+		return err
+	}
+	expectError := func(config map[string]interface{}, attribute string) {
+		t.Helper()
+		if err := plan(config); err == nil || !strings.Contains(err.Error(), attribute) {
+			t.Errorf("expected a policy error for %s, got %v", attribute, err)
+		}
+	}
+
+	// generated passwords default to the policy
+	if err := plan(map[string]interface{}{"title": "web", "password": []interface{}{map[string]interface{}{"generate": "true"}}}); err != nil {
+		t.Errorf("plan: %v", err)
+	}
+	expectError(map[string]interface{}{
+		"title": "web",
+		"password": []interface{}{map[string]interface{}{
+			"generate":   "true",
+			"complexity": []interface{}{map[string]interface{}{"length": 8}},
+		}},
+	}, "password.0.complexity.0.length")
+	expectError(map[string]interface{}{
+		"title": "web",
+		"password": []interface{}{map[string]interface{}{
+			"generate":   "true",
+			"complexity": []interface{}{map[string]interface{}{"length": 20, "digits": 1}},
+		}},
+	}, "password.0.complexity.0.digits")
+	expectError(map[string]interface{}{
+		"title":    "web",
+		"password": []interface{}{map[string]interface{}{"value": "short1"}},
+	}, "password.0.value")
+	expectError(map[string]interface{}{
+		"title":  "web",
+		"custom": []interface{}{map[string]interface{}{"type": "password", "label": "api", "value": "short"}},
+	}, "custom.0.value")
+
+	// unchanged passwords set before the policy still plan
+	config := map[string]interface{}{"title": "web", "password": []interface{}{map[string]interface{}{"value": "short1"}}}
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	d.SetId("uid")
+	config["title"] = "renamed"
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta); err != nil {
+		t.Errorf("plan without password changes: %v", err)
+	}
+}
+
+func TestEphemeralPasswordPolicy(t *testing.T) {
+	e := &ephemeralPassword{meta: providerMeta{passwordPolicy: &passwordPolicy{MinLength: 20, MinSpecial: 2, ForbiddenCharacters: "#"}}}
+	open := func(attributes map[string]interface{}) *ephemeral.OpenResponse {
+		return openTestEphemeral(t, e, attributes)
+	}
+
+	resp := open(map[string]interface{}{"special_characters": "#@"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("open: %v", resp.Diagnostics)
+	}
+	var data ephemeralPasswordModel
+	resp.Diagnostics.Append(resp.Result.Get(context.Background(), &data)...)
+	if password := data.Value.ValueString(); len(password) < 20 || strings.Contains(password, "#") || strings.Count(password, "@") < 2 {
+		t.Errorf("password %q doesn't meet the policy", password)
+	}
+
+	if resp := open(map[string]interface{}{"length": 12}); !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a length below the policy")
+	}
+	if resp := open(map[string]interface{}{"special_characters": "#"}); !resp.Diagnostics.HasError() {
+		t.Error("expected an error for special characters forbidden by the policy")
+	}
+}
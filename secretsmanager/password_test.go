@@ -0,0 +1,155 @@
+package secretsmanager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func intPointer(n int) *int {
+	return &n
+}
+
+func TestGeneratePasswordExcludeCharacters(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		password, err := generatePassword(passwordOptions{
+			Length:              32,
+			Caps:                intPointer(8),
+			Digits:              intPointer(8),
+			Special:             intPointer(8),
+			SpecialCharacters:   "#$%",
+			ExcludeCharacters:   "ABCDEF0123$",
+			MustStartWithLetter: true,
+		})
+		if err != nil {
+			t.Fatalf("generatePassword: %v", err)
+		}
+		if strings.ContainsAny(password, "ABCDEF0123$") {
+			t.Fatalf("password %q has excluded characters", password)
+		}
+		if countRunes(password, unicode.IsUpper) < 8 || countRunes(password, unicode.IsDigit) < 8 {
+			t.Fatalf("password %q lost the class counts", password)
+		}
+		if !unicode.IsLetter([]rune(password)[0]) {
+			t.Fatalf("password %q doesn't start with a letter", password)
+		}
+	}
+
+	// a fully excluded class is left out
+	password, err := generatePassword(passwordOptions{Length: 24, ExcludeCharacters: core.AsciiDigits})
+	if err != nil {
+		t.Fatalf("generatePassword: %v", err)
+	}
+	if strings.ContainsAny(password, core.AsciiDigits) {
+		t.Errorf("password %q has excluded digits", password)
+	}
+
+	if _, err := generatePassword(passwordOptions{Length: 24, Digits: intPointer(4), ExcludeCharacters: core.AsciiDigits}); err == nil {
+		t.Error("expected an error for digits with every digit excluded")
+	}
+	if _, err := generatePassword(passwordOptions{Length: 8, Lowercase: intPointer(0), Caps: intPointer(0), Digits: intPointer(8), Special: intPointer(0), MustStartWithLetter: true}); err == nil {
+		t.Error("expected an error for a password without letters that must start with one")
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	if len(passphraseWords) != 2048 {
+		t.Fatalf("expected 2048 words in the wordlist, got %d", len(passphraseWords))
+	}
+
+	passphrase, err := generatePassword(passwordOptions{Length: 4, Passphrase: &passphraseOptions{Separator: defaultPassphraseSeparator}})
+	if err != nil {
+		t.Fatalf("generatePassword: %v", err)
+	}
+	if words := strings.Split(passphrase, defaultPassphraseSeparator); len(words) != defaultPassphraseWords {
+		t.Errorf("passphrase %q doesn't have %d words", passphrase, defaultPassphraseWords)
+	}
+
+	passphrase, err = generatePassphrase(passphraseOptions{Words: 6, Separator: " ", Capitalize: true})
+	if err != nil {
+		t.Fatalf("generatePassphrase: %v", err)
+	}
+	words := strings.Split(passphrase, " ")
+	if len(words) != 6 {
+		t.Fatalf("passphrase %q doesn't have 6 words", passphrase)
+	}
+	for _, word := range words {
+		if !unicode.IsUpper([]rune(word)[0]) {
+			t.Errorf("word %q of %q isn't capitalized", word, passphrase)
+		}
+	}
+
+	if _, err := generatePassphrase(passphraseOptions{Words: minPassphraseWords - 1}); err == nil {
+		t.Error("expected an error for too few passphrase words")
+	}
+}
+
+func TestApplyGeneratePasswordOptions(t *testing.T) {
+	complexity := map[string]interface{}{
+		"length":                 20,
+		"caps":                   0,
+		"lowercase":              0,
+		"digits":                 0,
+		"special":                0,
+		"special_characters":     "",
+		"exclude_characters":     "aeiou",
+		"exclude_ambiguous":      true,
+		"must_start_with_letter": true,
+		"passphrase":             []interface{}{},
+	}
+	fieldData := []interface{}{map[string]interface{}{
+		"generate":   "true",
+		"complexity": []interface{}{complexity},
+	}}
+	field := &core.Password{Complexity: &core.PasswordComplexity{Length: 20}}
+	if generated, err := applyGeneratePassword(fieldData, field, nil); err != nil || !generated {
+		t.Fatalf("applyGeneratePassword = %v, %v", generated, err)
+	}
+	password := field.Value[0]
+	if len(password) != 20 || strings.ContainsAny(password, "aeiou"+ambiguousCharacters) || !unicode.IsLetter([]rune(password)[0]) {
+		t.Errorf("password %q doesn't match the complexity options", password)
+	}
+
+	// passphrase mode skips the length validation
+	complexity["passphrase"] = []interface{}{map[string]interface{}{"words": 4, "separator": ".", "capitalize": false}}
+	field = &core.Password{Complexity: &core.PasswordComplexity{}}
+	if _, err := applyGeneratePassword(fieldData, field, nil); err != nil {
+		t.Fatalf("applyGeneratePassword: %v", err)
+	}
+	if words := strings.Split(field.Value[0], "."); len(words) != 4 {
+		t.Errorf("passphrase %q doesn't have 4 words", field.Value[0])
+	}
+}
+
+func TestMergePasswordComplexityOptions(t *testing.T) {
+	schemaField := []interface{}{map[string]interface{}{
+		"generate": "true",
+		"complexity": []interface{}{map[string]interface{}{
+			"length":             20,
+			"exclude_characters": "0",
+			"exclude_ambiguous":  true,
+		}},
+	}}
+	// the record only stores the counts
+	recordField := []interface{}{map[string]interface{}{
+		"value":      "secret",
+		"complexity": []interface{}{map[string]interface{}{"length": float64(20)}},
+	}}
+	mergePassword(schemaField, recordField)
+
+	want := []interface{}{map[string]interface{}{
+		"value":    "secret",
+		"generate": "true",
+		"complexity": []interface{}{map[string]interface{}{
+			"length":             float64(20),
+			"exclude_characters": "0",
+			"exclude_ambiguous":  true,
+		}},
+	}}
+	if !reflect.DeepEqual(recordField, want) {
+		t.Errorf("merged = %v, want %v", recordField, want)
+	}
+}
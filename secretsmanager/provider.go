@@ -15,14 +15,33 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
 const MaxThrottledRetries = 32
 
+// defaultResourceTimeout is the default of every operation in the timeouts
+// block of the managed resources - same as the SDK default.
+const defaultResourceTimeout = 20 * time.Minute
+
+// resourceTimeouts returns the timeouts block of the resource. The SDK
+// cancels the context of an operation once it times out.
+func resourceTimeouts(r *schema.Resource) *schema.ResourceTimeout {
+	timeouts := &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultResourceTimeout),
+		Read:   schema.DefaultTimeout(defaultResourceTimeout),
+		Delete: schema.DefaultTimeout(defaultResourceTimeout),
+	}
+	if r.UpdateContext != nil {
+		timeouts.Update = schema.DefaultTimeout(defaultResourceTimeout)
+	}
+	return timeouts
+}
+
 // Provider returns the Keeper Secrets Manager Terraform provider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"credential": {
 				Type:        schema.TypeString,
@@ -31,6 +50,125 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("KEEPER_CREDENTIAL", nil),
 				Description: "Credential to use for Secrets Manager authentication. Can also be sourced from the `KEEPER_CREDENTIAL` environment variable.",
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: hostnameDescription,
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: proxyUrlDescription,
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: caCertFileDescription,
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: caCertPemDescription,
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: insecureSkipVerifyDescription,
+			},
+			"cache_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: cacheFileDescription,
+			},
+			"fallback_to_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: fallbackToCacheDescription,
+			},
+			"archive_folder_uid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: archiveFolderUidDescription,
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: auditLogPathDescription,
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  maxConcurrentRequestsDescription,
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: readOnlyDescription,
+			},
+			"allowed_write_folders": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: allowedWriteFoldersDescription,
+			},
+			"unmanaged_fields": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{UnmanagedFieldsRemove, UnmanagedFieldsPreserve}, false),
+				Description:  unmanagedFieldsDescription,
+			},
+			"include_file_content": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: includeFileContentProviderDescription,
+			},
+			"password_policy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: passwordPolicyDescription,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"min_length": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  minLengthDescription,
+						},
+						"min_caps": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  minCapsDescription,
+						},
+						"min_lowercase": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  minLowercaseDescription,
+						},
+						"min_digits": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  minDigitsDescription,
+						},
+						"min_special": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  minSpecialDescription,
+						},
+						"forbidden_characters": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: forbiddenCharactersDescription,
+						},
+					},
+				},
+			},
 		},
 		ConfigureContextFunc: providerConfigure,
 		DataSourcesMap: map[string]*schema.Resource{
@@ -62,6 +200,7 @@ func Provider() *schema.Provider {
 			"secretsmanager_software_license":     dataSourceSoftwareLicense(),
 			"secretsmanager_ssh_keys":             dataSourceSshKeys(),
 			"secretsmanager_ssn_card":             dataSourceSsnCard(),
+			"secretsmanager_template":             dataSourceTemplate(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"secretsmanager_address":              resourceAddress(),
@@ -90,6 +229,24 @@ func Provider() *schema.Provider {
 			"secretsmanager_ssn_card":             resourceSsnCard(),
 		},
 	}
+
+	// read_only and password_policy are checked at plan time for every managed resource
+	for name, r := range p.ResourcesMap {
+		if _, found := r.Schema["on_destroy"]; found {
+			r.CustomizeDiff = recordMoveCustomizeDiff(onDestroyCustomizeDiff(r.CustomizeDiff))
+		}
+		r.CustomizeDiff = readOnlyCustomizeDiff(passwordPolicyCustomizeDiff(r, r.CustomizeDiff))
+		r.Timeouts = resourceTimeouts(r)
+		setSchemaVersion(name, r)
+		r.CreateContext = logApiCalls("create "+name, auditResource(name, r.CreateContext))
+		r.ReadContext = logApiCalls("read "+name, r.ReadContext)
+		r.UpdateContext = logApiCalls("update "+name, auditResource(name, r.UpdateContext))
+		r.DeleteContext = logApiCalls("delete "+name, auditResource(name, r.DeleteContext))
+	}
+	for name, ds := range p.DataSourcesMap {
+		ds.ReadContext = logApiCalls("read "+name, ds.ReadContext)
+	}
+	return p
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -104,9 +261,60 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	if config.Get(core.KEY_APP_KEY) == "" || config.Get(core.KEY_CLIENT_ID) == "" || config.Get(core.KEY_PRIVATE_KEY) == "" {
 		return nil, diag.Errorf("Invalid credentials - please provide a valid base64 encoded KSM config. One-time tokens are not allowed.")
 	}
+	network := networkSettings{
+		Hostname:           d.Get("hostname").(string),
+		ProxyUrl:           d.Get("proxy_url").(string),
+		CaCertFile:         d.Get("ca_cert_file").(string),
+		CaCertPem:          d.Get("ca_cert_pem").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+	if err := configureNetwork(config, network.withEnvDefaults()); err != nil {
+		return nil, diag.FromErr(err)
+	}
 
-	client := core.NewSecretsManager(&core.ClientOptions{Config: config})
-	return providerMeta{client}, diags
+	cache := cacheSettings{
+		CacheFile:       d.Get("cache_file").(string),
+		FallbackToCache: d.Get("fallback_to_cache").(bool),
+	}
+	allowedFolders, err := GetStringList(d.Get("allowed_write_folders"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	policy := writePolicy{
+		ReadOnly:       d.Get("read_only").(bool),
+		AllowedFolders: allowedFolders,
+	}
+	auditLogPath := d.Get("audit_log_path").(string)
+	if auditLogPath == "" {
+		auditLogPath = envDefault("KEEPER_AUDIT_LOG_PATH")
+	}
+	limit, err := maxConcurrentRequests(d.Get("max_concurrent_requests").(int))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client, err := configureAuditLog(configureWritePolicy(configureSharedClient(newKsmClient(config), config, limit), policy.withEnvDefaults()), auditLogPath)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client, err = configureCache(client, config, cache.withEnvDefaults())
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	archiveFolderUid := d.Get("archive_folder_uid").(string)
+	if archiveFolderUid == "" {
+		archiveFolderUid = envDefault("KEEPER_ARCHIVE_FOLDER_UID")
+	}
+	passwordRules, err := passwordPolicyFromSchema(d.Get("password_policy"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return providerMeta{
+		client:           client,
+		unmanagedFields:  d.Get("unmanaged_fields").(string),
+		archiveFolderUid: strings.TrimSpace(archiveFolderUid),
+		passwordPolicy:   passwordRules,
+		skipFileContent:  !d.Get("include_file_content").(bool),
+	}, diags
 }
 
 func getConfiguredProvider(creds string) (*providerMeta, diag.Diagnostics) {
@@ -121,14 +329,34 @@ func getConfiguredProvider(creds string) (*providerMeta, diag.Diagnostics) {
 		return nil, diag.Errorf("Invalid credentials - please provide a valid base64 encoded KSM config. One-time tokens are not allowed.")
 	}
 
-	client := core.NewSecretsManager(&core.ClientOptions{Config: config})
-	return &providerMeta{client}, diags
+	client := newKsmClient(config)
+	return &providerMeta{client: client}, diags
 }
 
 type providerMeta struct {
-	client *core.SecretsManager
+	client ksmClient
+	// unmanagedFields controls what happens to custom fields not declared in
+	// configuration - one of UnmanagedFieldsRemove (default) or UnmanagedFieldsPreserve
+	unmanagedFields string
+	// archiveFolderUid is where records with on_destroy = "archive" are moved
+	archiveFolderUid string
+	// passwordPolicy is the minimum complexity of password fields - nil when
+	// not configured
+	passwordPolicy *passwordPolicy
+	// skipFileContent leaves content_base64 of data sources empty unless
+	// include_file_content is set
+	skipFileContent bool
 }
 
+const (
+	UnmanagedFieldsRemove   = "remove"
+	UnmanagedFieldsPreserve = "preserve"
+)
+
+const unmanagedFieldsDescription = "What to do with custom fields present in the vault but not declared in configuration " +
+	"(ex. added in Keeper UI). `remove` (default) shows them in the plan and removes them on the next apply, " +
+	"`preserve` leaves them untouched so Terraform only owns the fields it declares."
+
 // map attribute names from schema to field types in record v3
 var mapSchemaToRecordFieldName map[string]string = map[string]string{
 	"account_number":         "accountNumber", // Text
@@ -720,6 +948,191 @@ func customFieldsToDict(fields []interface{}) []interface{} {
 	return result
 }
 
+// customFieldKey identifies a custom field dict by its type and label.
+// Types are compared case-insensitively since state holds the canonical casing.
+func customFieldKey(item interface{}) string {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	fieldType, _ := m["type"].(string)
+	label, _ := m["label"].(string)
+	return strings.ToLower(fieldType) + "\x00" + label
+}
+
+func isIgnoredCustomLabel(item interface{}, ignored []string) bool {
+	if m, ok := item.(map[string]interface{}); ok {
+		label, _ := m["label"].(string)
+		for _, ignoredLabel := range ignored {
+			if strings.EqualFold(label, ignoredLabel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// managedCustomItems filters the custom fields read from the vault down to the ones
+// Terraform owns. Fields with ignored labels are always left out. In preserve mode
+// only fields matching a declared (type,label) are returned, in declaration order;
+// otherwise all remaining fields are returned in record order. The labels of fields
+// not declared (and not ignored) are returned separately for plan warnings.
+func managedCustomItems(items, declared []interface{}, ignored []string, mode string) (managed []interface{}, undeclared []string) {
+	candidates := []interface{}{}
+	for _, item := range items {
+		if !isIgnoredCustomLabel(item, ignored) {
+			candidates = append(candidates, item)
+		}
+	}
+
+	claimed := make([]bool, len(candidates))
+	for _, decl := range declared {
+		for i, item := range candidates {
+			if !claimed[i] && customFieldKey(item) == customFieldKey(decl) {
+				claimed[i] = true
+				if mode == UnmanagedFieldsPreserve {
+					managed = append(managed, item)
+				}
+				break
+			}
+		}
+	}
+	for i, item := range candidates {
+		if !claimed[i] {
+			label, _ := item.(map[string]interface{})["label"].(string)
+			undeclared = append(undeclared, label)
+		}
+	}
+
+	if mode != UnmanagedFieldsPreserve {
+		managed = candidates
+	}
+	if managed == nil {
+		managed = []interface{}{}
+	}
+	return managed, undeclared
+}
+
+// mergeCustomFields builds the custom section written on update from the current
+// vault fields, the previously declared fields and the newly declared ones (as dicts).
+// Fields with ignored labels are always kept in place. In preserve mode declared
+// fields are replaced in place or dropped when removed from configuration, fields
+// never declared are kept and new ones are appended. Otherwise the section holds
+// only the ignored fields followed by the declared ones.
+func mergeCustomFields(current, declared, fields []interface{}, ignored []string, mode string) []interface{} {
+	result := []interface{}{}
+	if mode != UnmanagedFieldsPreserve {
+		for _, item := range current {
+			if isIgnoredCustomLabel(item, ignored) {
+				result = append(result, item)
+			}
+		}
+		return append(result, fields...)
+	}
+
+	declaredKeys := map[string]bool{}
+	for _, item := range declared {
+		declaredKeys[customFieldKey(item)] = true
+	}
+	used := make([]bool, len(fields))
+	for _, item := range current {
+		if isIgnoredCustomLabel(item, ignored) {
+			result = append(result, item)
+			continue
+		}
+		key := customFieldKey(item)
+		replaced := false
+		for i, field := range fields {
+			if !used[i] && customFieldKey(field) == key {
+				used[i] = true
+				replaced = true
+				result = append(result, field)
+				break
+			}
+		}
+		if !replaced && !declaredKeys[key] {
+			result = append(result, item)
+		}
+	}
+	for i, field := range fields {
+		if !used[i] {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// customIgnoredLabels returns the resource's ignore_custom_labels plus any
+// platform-managed labels the resource keeps out of the user-visible custom list.
+func customIgnoredLabels(d *schema.ResourceData, reserved ...string) []string {
+	ignored := append([]string{}, reserved...)
+	if labels, ok := d.Get("ignore_custom_labels").([]interface{}); ok {
+		for _, label := range labels {
+			if s, ok := label.(string); ok {
+				ignored = append(ignored, s)
+			}
+		}
+	}
+	return ignored
+}
+
+// recordImportKey marks the context of the read done by a resource importer.
+type recordImportKey struct{}
+
+// withRecordImport returns the context for the read done by a resource importer.
+func withRecordImport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recordImportKey{}, true)
+}
+
+// isRecordImport reports whether the read is done by a resource importer -
+// there is no prior state then.
+func isRecordImport(ctx context.Context) bool {
+	importing, _ := ctx.Value(recordImportKey{}).(bool)
+	return importing
+}
+
+// setCustomResourceData sets the custom fields Terraform owns into resource data.
+// On import there is no prior state to tell which fields Terraform owns, so in
+// preserve mode the imported state holds all the fields not ignored. Undeclared
+// fields the next apply would remove are warned about at plan time - see
+// undeclaredCustomLabels.
+func setCustomResourceData(ctx context.Context, d *schema.ResourceData, secret *core.Record, mode string, reserved ...string) diag.Diagnostics {
+	declared, _ := d.Get("custom").([]interface{})
+	items := getFieldItemsData(secret.RecordDict, "custom")
+	if isRecordImport(ctx) {
+		declared = items
+	}
+	managed, _ := managedCustomItems(items, declared, customIgnoredLabels(d, reserved...), mode)
+	if err := d.Set("custom", managed); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// applyCustomFieldsChange writes the declared custom fields into the record
+// honoring the unmanaged_fields mode and ignore_custom_labels.
+func applyCustomFieldsChange(d *schema.ResourceData, secret *core.Record, mode string, reserved ...string) error {
+	oldData, newData := d.GetChange("custom")
+	fields, err := customFieldsFromSchema(newData.([]interface{}))
+	if err != nil {
+		return err
+	}
+	current, _ := secret.RecordDict["custom"].([]interface{})
+	secret.RecordDict["custom"] = mergeCustomFields(current, oldData.([]interface{}), customFieldsToDict(fields), customIgnoredLabels(d, reserved...), mode)
+	return nil
+}
+
+// undeclaredCustomLabels returns the labels of the custom fields in state that
+// the planned apply removes because configuration doesn't declare them - only
+// in remove mode, preserve mode keeps them. State holds the fields not ignored.
+func undeclaredCustomLabels(state, declared []interface{}, ignored []string, mode string) []string {
+	if mode == UnmanagedFieldsPreserve {
+		return nil
+	}
+	_, undeclared := managedCustomItems(state, declared, ignored, mode)
+	return undeclared
+}
+
 // pamReservedCustomLabels contains labels that pam_machine and pam_user inject
 // as platform-managed custom fields. User config must not reuse these labels.
 var pamReservedCustomLabels = map[string]bool{
@@ -874,6 +1287,8 @@ func getCardRefItemData(secret *core.Record, uid string) []interface{} {
 	return items
 }
 
+// getFileItemsData returns the file_ref items of the typed data sources with
+// the content of every attachment - their file_ref schema has no sha256.
 func getFileItemsData(fileItems []*core.KeeperFile) []interface{} {
 	if len(fileItems) == 0 {
 		return []interface{}{}
@@ -910,17 +1325,20 @@ func getHostItemData(secret *core.Record) []interface{} {
 		return []interface{}{}
 	}
 
+	// host is multi-value - return all entries in record order
 	items := []interface{}{}
-	if values, ok := fields[0]["value"].([]interface{}); ok && len(values) > 0 {
-		if vmap, ok := values[0].(map[string]interface{}); ok {
-			item := map[string]interface{}{}
-			if val, ok := vmap["hostName"].(string); ok {
-				item["host_name"] = val
-			}
-			if val, ok := vmap["port"].(string); ok {
-				item["port"] = val
+	if values, ok := fields[0]["value"].([]interface{}); ok {
+		for _, value := range values {
+			if vmap, ok := value.(map[string]interface{}); ok {
+				item := map[string]interface{}{}
+				if val, ok := vmap["hostName"].(string); ok {
+					item["host_name"] = val
+				}
+				if val, ok := vmap["port"].(string); ok {
+					item["port"] = val
+				}
+				items = append(items, item)
 			}
-			items = []interface{}{item}
 		}
 	}
 
@@ -1008,29 +1426,41 @@ func getPhoneItemData(secret *core.Record) []interface{} {
 		return []interface{}{}
 	}
 
+	// phone is multi-value - return all entries in record order
 	items := []interface{}{}
-	if values, ok := fields[0]["value"].([]interface{}); ok && len(values) > 0 {
-		if vmap, ok := values[0].(map[string]interface{}); ok {
-			item := map[string]interface{}{}
-			if val, ok := vmap["region"].(string); ok {
-				item["region"] = val
-			}
-			if val, ok := vmap["number"].(string); ok {
-				item["number"] = val
-			}
-			if val, ok := vmap["ext"].(string); ok {
-				item["ext"] = val
-			}
-			if val, ok := vmap["type"].(string); ok {
-				item["type"] = val
+	if values, ok := fields[0]["value"].([]interface{}); ok {
+		for _, value := range values {
+			if vmap, ok := value.(map[string]interface{}); ok {
+				item := map[string]interface{}{}
+				if val, ok := vmap["region"].(string); ok {
+					item["region"] = val
+				}
+				if val, ok := vmap["number"].(string); ok {
+					item["number"] = val
+				}
+				if val, ok := vmap["ext"].(string); ok {
+					item["ext"] = val
+				}
+				if val, ok := vmap["type"].(string); ok {
+					item["type"] = val
+				}
+				items = append(items, item)
 			}
-			items = []interface{}{item}
 		}
 	}
 
 	return items
 }
 
+// multiValueFieldTypes lists complex field types where Keeper allows more than one
+// value per field (ex. several phone numbers or hosts). All values are read and
+// written in record order so plans don't churn.
+var multiValueFieldTypes = map[string]bool{
+	"host":             true,
+	"phone":            true,
+	"securityQuestion": true,
+}
+
 func getFieldDicts(fieldType, section string, recordDict map[string]interface{}) []interface{} {
 	result := []interface{}{}
 	if flds, found := recordDict[section]; found {
@@ -1049,200 +1479,213 @@ func getFieldDicts(fieldType, section string, recordDict map[string]interface{})
 
 func getFieldResourceData(fieldType, section string, secret *core.Record) interface{} {
 	if flds := getFieldDicts(fieldType, section, secret.RecordDict); len(flds) > 0 {
-		if fieldSchema := parseFieldFromDataJson(flds[0]); fieldSchema != nil {
-			ftSchema := map[string]interface{}{
-				"type": fieldType,
-			}
-			if _, found := fieldSchema.fields["label"]; found {
-				ftSchema["label"] = fieldSchema.Label
-			}
-			if _, found := fieldSchema.fields["required"]; found {
-				ftSchema["required"] = fieldSchema.Required
-			}
-			if _, found := fieldSchema.fields["privacyScreen"]; found {
-				ftSchema["privacy_screen"] = fieldSchema.PrivacyScreen
-			}
-			if _, found := fieldSchema.fields["enforceGeneration"]; found {
-				ftSchema["enforce_generation"] = fieldSchema.EnforceGeneration
-			}
-			if _, found := fieldSchema.fields["complexity"]; found && len(fieldSchema.Complexity) > 0 {
-				complexity := fieldSchema.Complexity[0]
-				cmap := map[string]interface{}{
-					"length":    complexity.Length,
-					"caps":      complexity.Caps,
-					"lowercase": complexity.Lowercase,
-					"digits":    complexity.Digits,
-					"special":   complexity.Special,
-				}
-				ftSchema["complexity"] = []interface{}{cmap}
-			}
-			if _, found := fieldSchema.fields["value"]; found {
-				fis := []interface{}{}
-				if fi, ok := fieldSchema.Value.([]interface{}); ok {
-					for _, fiv := range fi {
-						if str, ok := fiv.(string); ok && str != "" {
-							// simple value - string
-							ftSchema["value"] = str
-						} else if num, ok := fiv.(float64); ok {
-							// simple value - Int64 (converted to float/float64 by JSON)
-							ftSchema["value"] = int64(num)
-						} else if fmap, ok := fiv.(map[string]interface{}); ok && len(fmap) > 0 {
-							// complex value - map struct fields to schema
-							fv := map[string]interface{}{}
-							switch fieldType {
-							case "address":
-								if str, ok := fmap["street1"]; ok {
-									fv["street1"] = str
-								}
-								if str, ok := fmap["street2"]; ok {
-									fv["street2"] = str
-								}
-								if str, ok := fmap["city"]; ok {
-									fv["city"] = str
-								}
-								if str, ok := fmap["state"]; ok {
-									fv["state"] = str
-								}
-								if str, ok := fmap["country"]; ok {
-									fv["country"] = str
-								}
-								if str, ok := fmap["zip"]; ok {
-									fv["zip"] = str
-								}
-							case "bankAccount":
-								if str, ok := fmap["accountType"]; ok {
-									fv["account_type"] = str
-								}
-								if str, ok := fmap["routingNumber"]; ok {
-									fv["routing_number"] = str
-								}
-								if str, ok := fmap["accountNumber"]; ok {
-									fv["account_number"] = str
-								}
-								if str, ok := fmap["otherType"]; ok {
-									fv["other_type"] = str
-								}
-							case "host":
-								if str, ok := fmap["hostName"]; ok {
-									fv["host_name"] = str
-								}
-								if str, ok := fmap["port"]; ok {
-									fv["port"] = str
-								}
-							case "keyPair":
-								if str, ok := fmap["publicKey"]; ok {
-									fv["public_key"] = str
-								}
-								if str, ok := fmap["privateKey"]; ok {
-									fv["private_key"] = str
-								}
-							case "name":
-								if str, ok := fmap["first"]; ok {
-									fv["first"] = str
-								}
-								if str, ok := fmap["middle"]; ok {
-									fv["middle"] = str
-								}
-								if str, ok := fmap["last"]; ok {
-									fv["last"] = str
-								}
-							case "paymentCard":
-								if str, ok := fmap["cardNumber"]; ok {
-									fv["card_number"] = str
-								}
-								if str, ok := fmap["cardExpirationDate"]; ok {
-									fv["card_expiration_date"] = str
-								}
-								if str, ok := fmap["cardSecurityCode"]; ok {
-									fv["card_security_code"] = str
-								}
-							case "phone":
-								if str, ok := fmap["region"]; ok {
-									fv["region"] = str
-								}
-								if str, ok := fmap["number"]; ok {
-									fv["number"] = str
-								}
-								if str, ok := fmap["ext"]; ok {
-									fv["ext"] = str
-								}
-								if str, ok := fmap["type"]; ok {
-									fv["type"] = str
-								}
-							case "securityQuestion":
-								if str, ok := fmap["question"]; ok {
-									fv["question"] = str
-								}
-								if str, ok := fmap["answer"]; ok {
-									fv["answer"] = str
-								}
-							case "script":
-								if str, ok := fmap["fileRef"]; ok {
-									fv["file_ref"] = str
+		if ftSchema := fieldDictToResourceData(fieldType, flds[0]); ftSchema != nil {
+			return []interface{}{ftSchema}
+		}
+	}
+	return []interface{}{}
+}
+
+// fieldDictToResourceData converts a single record field (as found in RecordDict)
+// into its schema representation, or returns nil if the field can't be parsed
+func fieldDictToResourceData(fieldType string, fld interface{}) map[string]interface{} {
+	if fieldSchema := parseFieldFromDataJson(fld); fieldSchema != nil {
+		ftSchema := map[string]interface{}{
+			"type": fieldType,
+		}
+		if _, found := fieldSchema.fields["label"]; found {
+			ftSchema["label"] = fieldSchema.Label
+		}
+		if _, found := fieldSchema.fields["required"]; found {
+			ftSchema["required"] = fieldSchema.Required
+		}
+		if _, found := fieldSchema.fields["privacyScreen"]; found {
+			ftSchema["privacy_screen"] = fieldSchema.PrivacyScreen
+		}
+		if _, found := fieldSchema.fields["enforceGeneration"]; found {
+			ftSchema["enforce_generation"] = fieldSchema.EnforceGeneration
+		}
+		if _, found := fieldSchema.fields["complexity"]; found && len(fieldSchema.Complexity) > 0 {
+			complexity := fieldSchema.Complexity[0]
+			cmap := map[string]interface{}{
+				"length":    complexity.Length,
+				"caps":      complexity.Caps,
+				"lowercase": complexity.Lowercase,
+				"digits":    complexity.Digits,
+				"special":   complexity.Special,
+			}
+			ftSchema["complexity"] = []interface{}{cmap}
+		}
+		if _, found := fieldSchema.fields["value"]; found {
+			fis := []interface{}{}
+			if fi, ok := fieldSchema.Value.([]interface{}); ok {
+				for _, fiv := range fi {
+					if str, ok := fiv.(string); ok && str != "" {
+						// simple value - string
+						ftSchema["value"] = str
+					} else if num, ok := fiv.(float64); ok {
+						// simple value - Int64 (converted to float/float64 by JSON)
+						ftSchema["value"] = int64(num)
+					} else if fmap, ok := fiv.(map[string]interface{}); ok && len(fmap) > 0 {
+						// complex value - map struct fields to schema
+						fv := map[string]interface{}{}
+						switch fieldType {
+						case "address":
+							if str, ok := fmap["street1"]; ok {
+								fv["street1"] = str
+							}
+							if str, ok := fmap["street2"]; ok {
+								fv["street2"] = str
+							}
+							if str, ok := fmap["city"]; ok {
+								fv["city"] = str
+							}
+							if str, ok := fmap["state"]; ok {
+								fv["state"] = str
+							}
+							if str, ok := fmap["country"]; ok {
+								fv["country"] = str
+							}
+							if str, ok := fmap["zip"]; ok {
+								fv["zip"] = str
+							}
+						case "bankAccount":
+							if str, ok := fmap["accountType"]; ok {
+								fv["account_type"] = str
+							}
+							if str, ok := fmap["routingNumber"]; ok {
+								fv["routing_number"] = str
+							}
+							if str, ok := fmap["accountNumber"]; ok {
+								fv["account_number"] = str
+							}
+							if str, ok := fmap["otherType"]; ok {
+								fv["other_type"] = str
+							}
+						case "host":
+							if str, ok := fmap["hostName"]; ok {
+								fv["host_name"] = str
+							}
+							if str, ok := fmap["port"]; ok {
+								fv["port"] = str
+							}
+						case "keyPair":
+							if str, ok := fmap["publicKey"]; ok {
+								fv["public_key"] = str
+							}
+							if str, ok := fmap["privateKey"]; ok {
+								fv["private_key"] = str
+							}
+						case "name":
+							if str, ok := fmap["first"]; ok {
+								fv["first"] = str
+							}
+							if str, ok := fmap["middle"]; ok {
+								fv["middle"] = str
+							}
+							if str, ok := fmap["last"]; ok {
+								fv["last"] = str
+							}
+						case "paymentCard":
+							if str, ok := fmap["cardNumber"]; ok {
+								fv["card_number"] = str
+							}
+							if str, ok := fmap["cardExpirationDate"]; ok {
+								fv["card_expiration_date"] = str
+							}
+							if str, ok := fmap["cardSecurityCode"]; ok {
+								fv["card_security_code"] = str
+							}
+						case "phone":
+							if str, ok := fmap["region"]; ok {
+								fv["region"] = str
+							}
+							if str, ok := fmap["number"]; ok {
+								fv["number"] = str
+							}
+							if str, ok := fmap["ext"]; ok {
+								fv["ext"] = str
+							}
+							if str, ok := fmap["type"]; ok {
+								fv["type"] = str
+							}
+						case "securityQuestion":
+							if str, ok := fmap["question"]; ok {
+								fv["question"] = str
+							}
+							if str, ok := fmap["answer"]; ok {
+								fv["answer"] = str
+							}
+						case "script":
+							if str, ok := fmap["fileRef"]; ok {
+								fv["file_ref"] = str
+							}
+							if str, ok := fmap["command"]; ok {
+								fv["command"] = str
+							}
+							if refs, ok := fmap["recordRef"]; ok {
+								if refList, ok := refs.([]interface{}); ok {
+									fv["record_ref"] = refList
 								}
-								if str, ok := fmap["command"]; ok {
-									fv["command"] = str
+							}
+						case "pamHostname":
+							if str, ok := fmap["hostName"]; ok {
+								fv["hostname"] = str
+							}
+							if str, ok := fmap["port"]; ok {
+								fv["port"] = str
+							}
+						case "pamResources":
+							if str, ok := fmap["controllerUid"]; ok {
+								fv["controller_uid"] = str
+							}
+							if str, ok := fmap["folderUid"]; ok {
+								fv["folder_uid"] = str
+							}
+							if refs, ok := fmap["resourceRef"]; ok {
+								if refList, ok := refs.([]interface{}); ok {
+									fv["resource_ref"] = refList
 								}
-								if refs, ok := fmap["recordRef"]; ok {
-									if refList, ok := refs.([]interface{}); ok {
-										fv["record_ref"] = refList
+							}
+							if settings, ok := fmap["allowedSettings"]; ok {
+								if settingsMap, ok := settings.(map[string]interface{}); ok {
+									if val, ok := settingsMap["connections"]; ok {
+										fv["allowed_connections"] = val
 									}
-								}
-							case "pamHostname":
-								if str, ok := fmap["hostName"]; ok {
-									fv["hostname"] = str
-								}
-								if str, ok := fmap["port"]; ok {
-									fv["port"] = str
-								}
-							case "pamResources":
-								if str, ok := fmap["controllerUid"]; ok {
-									fv["controller_uid"] = str
-								}
-								if str, ok := fmap["folderUid"]; ok {
-									fv["folder_uid"] = str
-								}
-								if refs, ok := fmap["resourceRef"]; ok {
-									if refList, ok := refs.([]interface{}); ok {
-										fv["resource_ref"] = refList
+									if val, ok := settingsMap["portForwards"]; ok {
+										fv["allowed_port_forwards"] = val
 									}
-								}
-								if settings, ok := fmap["allowedSettings"]; ok {
-									if settingsMap, ok := settings.(map[string]interface{}); ok {
-										if val, ok := settingsMap["connections"]; ok {
-											fv["allowed_connections"] = val
-										}
-										if val, ok := settingsMap["portForwards"]; ok {
-											fv["allowed_port_forwards"] = val
-										}
-										if val, ok := settingsMap["rotation"]; ok {
-											fv["allowed_rotation"] = val
-										}
-										if val, ok := settingsMap["sessionRecording"]; ok {
-											fv["allowed_session_recording"] = val
-										}
-										if val, ok := settingsMap["typescriptRecording"]; ok {
-											fv["allowed_typescript_recording"] = val
-										}
+									if val, ok := settingsMap["rotation"]; ok {
+										fv["allowed_rotation"] = val
+									}
+									if val, ok := settingsMap["sessionRecording"]; ok {
+										fv["allowed_session_recording"] = val
+									}
+									if val, ok := settingsMap["typescriptRecording"]; ok {
+										fv["allowed_typescript_recording"] = val
 									}
 								}
-							default:
-								fv = nil
-							}
-							if len(fv) > 0 {
-								fis = append(fis, fv)
 							}
+						default:
+							fv = nil
+						}
+						if len(fv) > 0 {
+							fis = append(fis, fv)
 						}
 					}
 				}
-				if len(fis) > 0 {
+			}
+			if len(fis) > 0 {
+				if multiValueFieldTypes[fieldType] {
+					ftSchema["value"] = fis
+				} else {
 					ftSchema["value"] = []interface{}{fis[0]}
 				}
 			}
-			return []interface{}{ftSchema}
 		}
+		return ftSchema
 	}
-	return []interface{}{}
+	return nil
 }
 
 func getFieldResourceDataWithLabel(fieldType, section string, secret *core.Record, label string) interface{} {
@@ -1321,6 +1764,95 @@ func getFieldResourceDataWithLabel(fieldType, section string, secret *core.Recor
 	return []interface{}{}
 }
 
+// repeatableFieldSchemas lists schema attributes that may be repeated to store several
+// fields of the same type (ex. multiple url fields) distinguished by their labels
+var repeatableFieldSchemas = map[string]bool{
+	"email": true,
+	"url":   true,
+}
+
+// getFieldsResourceData returns all fields of fieldType in schema representation for
+// a repeatable field block. Configured blocks with a label are read with
+// getFieldResourceDataWithLabel, unlabeled blocks take the remaining fields by position,
+// and any fields left over (ex. added in the vault) are appended in record order so
+// drift is visible in plans.
+func getFieldsResourceData(fieldType, section string, secret *core.Record, configured interface{}) []interface{} {
+	flds := getFieldDicts(fieldType, section, secret.RecordDict)
+	if len(flds) == 0 {
+		return []interface{}{}
+	}
+
+	labels := []string{}
+	if blocks, ok := configured.([]interface{}); ok {
+		for _, block := range blocks {
+			label := ""
+			if bmap, ok := block.(map[string]interface{}); ok {
+				label, _ = bmap["label"].(string)
+			}
+			labels = append(labels, label)
+		}
+	}
+
+	// the first field with a configured label is read by label - the others by position
+	recordLabels := map[string]bool{}
+	for _, fld := range flds {
+		if fmap, ok := fld.(map[string]interface{}); ok {
+			if label, ok := fmap["label"].(string); ok {
+				recordLabels[label] = true
+			}
+		}
+	}
+	matched := map[string]bool{}
+	for _, label := range labels {
+		if label != "" && recordLabels[label] {
+			matched[label] = true
+		}
+	}
+	rest := []interface{}{}
+	taken := map[string]bool{}
+	for _, fld := range flds {
+		label := ""
+		if fmap, ok := fld.(map[string]interface{}); ok {
+			label, _ = fmap["label"].(string)
+		}
+		if matched[label] && !taken[label] {
+			taken[label] = true
+			continue
+		}
+		rest = append(rest, fld)
+	}
+
+	result := []interface{}{}
+	for _, label := range labels {
+		if matched[label] {
+			if items, ok := getFieldResourceDataWithLabel(fieldType, section, secret, label).([]interface{}); ok {
+				result = append(result, items...)
+			}
+		} else if len(rest) > 0 {
+			if ftSchema := fieldDictToResourceData(fieldType, rest[0]); ftSchema != nil {
+				result = append(result, ftSchema)
+			}
+			rest = rest[1:]
+		}
+	}
+	for _, fld := range rest {
+		if ftSchema := fieldDictToResourceData(fieldType, fld); ftSchema != nil {
+			result = append(result, ftSchema)
+		}
+	}
+	return result
+}
+
+// getLabeledFieldResourceData returns the field of fieldType with the label of the
+// configured block, or the first field of fieldType when no label is configured -
+// ex. a contact company next to other text fields.
+func getLabeledFieldResourceData(fieldType, section string, secret *core.Record, configured interface{}) interface{} {
+	if label, _ := firstSchemaBlock(configured)["label"].(string); label != "" {
+		return getFieldResourceDataWithLabel(fieldType, section, secret, label)
+	}
+	return getFieldResourceData(fieldType, section, secret)
+}
+
 func getFileItemsResourceData(secret *core.Record) []interface{} {
 	if flds := getFieldDicts("fileRef", "fields", secret.RecordDict); len(flds) > 0 {
 		fieldSchema := parseFieldFromDataJson(flds[0])
@@ -1370,7 +1902,7 @@ func getFileItemsResourceData(secret *core.Record) []interface{} {
 	return []interface{}{}
 }
 
-func getRecord(path string, title string, client core.SecretsManager) (secret *core.Record, e error) {
+func getRecord(ctx context.Context, path string, title string, client ksmClient) (secret *core.Record, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			secret = nil
@@ -1389,7 +1921,7 @@ func getRecord(path string, title string, client core.SecretsManager) (secret *c
 	title = strings.TrimSpace(title)
 	path = strings.TrimSpace(path)
 	if title != "" && path == "*" { // find by title requested
-		secrets, err := getSecrets(client, []string{})
+		secrets, err := getSecrets(ctx, client, []string{})
 		if err != nil {
 			return nil, err
 		}
@@ -1410,7 +1942,7 @@ func getRecord(path string, title string, client core.SecretsManager) (secret *c
 		}
 		return secret, nil
 	} else { // find by UID
-		secrets, err := getSecrets(client, []string{path})
+		secrets, err := getSecrets(ctx, client, []string{path})
 		if err != nil {
 			return nil, err
 		}
@@ -1435,15 +1967,15 @@ func getRecord(path string, title string, client core.SecretsManager) (secret *c
 	}
 }
 
-func createRecord(recordUid string, folderUid string, record *core.RecordCreate, client core.SecretsManager) (string, error) {
-	co, err := buildCreateOptions(folderUid, client, nil)
+func createRecord(ctx context.Context, recordUid string, folderUid string, record *core.RecordCreate, client ksmClient) (string, error) {
+	co, err := buildCreateOptions(ctx, folderUid, client, nil)
 	if err != nil {
 		return "", err
 	}
-	return createSecretWithRecordDataUidAndOptions(client, recordUid, co, record, nil)
+	return createSecretWithRecordDataUidAndOptions(ctx, client, recordUid, co, record, nil)
 }
 
-func saveRecord(record *core.Record, client core.SecretsManager) (e error) {
+func saveRecord(ctx context.Context, record *core.Record, client ksmClient) (e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -1457,19 +1989,33 @@ func saveRecord(record *core.Record, client core.SecretsManager) (e error) {
 		}
 	}()
 
+	if e = writePolicyOf(client).checkRecord(ctx, record, client); e != nil {
+		return e
+	}
+
+	call := startApiCall(client, "Save", map[string]interface{}{"record_uid": record.Uid})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		e = client.Save(record)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	auditTrailOf(client).recordSaved(record, e)
 	return e
 }
 
-func deleteRecord(recordUid string, client core.SecretsManager) (e error) {
+func deleteRecord(ctx context.Context, recordUid string, client ksmClient) (e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -1483,17 +2029,36 @@ func deleteRecord(recordUid string, client core.SecretsManager) (e error) {
 		}
 	}()
 
+	if e = writePolicyOf(client).checkRecordUid(ctx, recordUid, client); e != nil {
+		return e
+	}
+	trail := auditTrailOf(client)
+	if !trail.remembers(recordUid) {
+		// fetched for the record type and folder of the audit entry
+		_, _ = getSecrets(ctx, client, []string{recordUid})
+	}
+
 	statuses := map[string]string{}
 
+	call := startApiCall(client, "DeleteSecrets", map[string]interface{}{"record_uids": []string{recordUid}})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		statuses, e = client.DeleteSecrets([]string{recordUid})
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	defer func() { trail.recordDeleted(recordUid, e) }()
 
 	if e != nil {
 		return e
@@ -1513,9 +2078,9 @@ func deleteRecord(recordUid string, client core.SecretsManager) (e error) {
 }
 
 // Lookup folders by name or UID where parentFolder (if present) is direct parent folder (name or UID)
-func findFolder(parentFolder, folderUid, folderName string, client core.SecretsManager) (folders []*core.KeeperFolder, e error) {
+func findFolder(ctx context.Context, parentFolder, folderUid, folderName string, client ksmClient) (folders []*core.KeeperFolder, e error) {
 	folders = []*core.KeeperFolder{}
-	allFolders, e := getFolders(client)
+	allFolders, e := getFolders(ctx, client)
 	if e != nil {
 		return folders, e
 	}
@@ -1548,9 +2113,9 @@ func findFolder(parentFolder, folderUid, folderName string, client core.SecretsM
 	return folders, nil
 }
 
-func findSubFolder(parentFolderUid, folderUid, folderName string, client core.SecretsManager) (folders []*core.KeeperFolder, e error) {
+func findSubFolder(ctx context.Context, parentFolderUid, folderUid, folderName string, client ksmClient) (folders []*core.KeeperFolder, e error) {
 	folders = []*core.KeeperFolder{}
-	allFolders, e := getFolders(client)
+	allFolders, e := getFolders(ctx, client)
 	if e != nil {
 		return folders, e
 	}
@@ -1574,9 +2139,9 @@ func findSubFolder(parentFolderUid, folderUid, folderName string, client core.Se
 	return folders, nil
 }
 
-func createFolder(parentFolder, folderName string, client core.SecretsManager) (folderUid string, e error) {
+func createFolder(ctx context.Context, parentFolder, folderName string, client ksmClient) (folderUid string, e error) {
 	folderUid = ""
-	folders, err := getFolders(client)
+	folders, err := getFolders(ctx, client)
 	if err != nil {
 		return folderUid, err
 	}
@@ -1600,15 +2165,18 @@ func createFolder(parentFolder, folderName string, client core.SecretsManager) (
 		break
 	}
 
-	co, err := buildCreateOptions(folderUid, client, folders)
+	co, err := buildCreateOptions(ctx, folderUid, client, folders)
 	if err != nil {
 		return folderUid, err
 	}
-	return createFolderWithOptions(client, co, folderName, folders)
+	return createFolderWithOptions(ctx, client, co, folderName, folders)
 }
 
-func deleteFolder(folderUid string, forceDelete bool, client core.SecretsManager) (e error) {
-	statuses, err := deleteFolders(client, []string{folderUid}, forceDelete)
+func deleteFolder(ctx context.Context, folderUid string, forceDelete bool, client ksmClient) (e error) {
+	if err := writePolicyOf(client).checkFolder(ctx, folderUid, nil, client); err != nil {
+		return err
+	}
+	statuses, err := deleteFolders(ctx, client, []string{folderUid}, forceDelete)
 	if err != nil {
 		return err
 	}
@@ -1628,7 +2196,7 @@ func deleteFolder(folderUid string, forceDelete bool, client core.SecretsManager
 
 /*
 // deprecated - use NewRecordCreate
-func getTemplateRecord(folderUid string, recordType string, templateTitle string, client core.SecretsManager) (secret *core.Record, e error) {
+func getTemplateRecord(ctx context.Context, folderUid string, recordType string, templateTitle string, client ksmClient) (secret *core.Record, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			secret = nil
@@ -1648,7 +2216,7 @@ func getTemplateRecord(folderUid string, recordType string, templateTitle string
 	folderUid = strings.TrimSpace(folderUid)
 	recordType = strings.TrimSpace(recordType)
 	if folderUid != "" && recordType != "" {
-		secrets, err := getSecrets(client, []string{})
+		secrets, err := getSecrets(ctx, client, []string{})
 		if err != nil {
 			return nil, err
 		}
@@ -1682,7 +2250,7 @@ func getTemplateRecord(folderUid string, recordType string, templateTitle string
 }
 */
 
-func getTemplateFolder(folderUid string, client core.SecretsManager) (fuid string, e error) {
+func getTemplateFolder(ctx context.Context, folderUid string, client ksmClient) (fuid string, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			fuid = ""
@@ -1701,7 +2269,7 @@ func getTemplateFolder(folderUid string, client core.SecretsManager) (fuid strin
 	fuid = ""
 	folderUid = strings.TrimSpace(folderUid)
 	if folderUid == "" || folderUid == "*" {
-		secrets, err := getSecrets(client, []string{})
+		secrets, err := getSecrets(ctx, client, []string{})
 		if err != nil {
 			return "", err
 		}
@@ -1723,10 +2291,10 @@ func getTemplateFolder(folderUid string, client core.SecretsManager) (fuid strin
 }
 
 // getSharedFolder tries to find closest parent shared folder
-func getSharedFolder(folderUid string, client core.SecretsManager, folders []*core.KeeperFolder) (fuid string, e error) {
+func getSharedFolder(ctx context.Context, folderUid string, client ksmClient, folders []*core.KeeperFolder) (fuid string, e error) {
 	folderUid = strings.TrimSpace(folderUid)
 	if len(folders) == 0 {
-		if folders, e = getFolders(client); e != nil {
+		if folders, e = getFolders(ctx, client); e != nil {
 			return "", e
 		}
 	}
@@ -1773,14 +2341,18 @@ func getSharedFolder(folderUid string, client core.SecretsManager, folders []*co
 }
 
 // buildCreateOptions finds parent shared folder and returns CreateOptions
-func buildCreateOptions(folderUid string, client core.SecretsManager, folders []*core.KeeperFolder) (co *core.CreateOptions, e error) {
+func buildCreateOptions(ctx context.Context, folderUid string, client ksmClient, folders []*core.KeeperFolder) (co *core.CreateOptions, e error) {
 	if len(folders) == 0 {
-		if folders, e = getFolders(client); e != nil {
+		if folders, e = getFolders(ctx, client); e != nil {
 			return nil, e
 		}
 	}
 
-	fuid, err := getSharedFolder(folderUid, client, folders)
+	if err := writePolicyOf(client).checkFolder(ctx, folderUid, folders, client); err != nil {
+		return nil, err
+	}
+
+	fuid, err := getSharedFolder(ctx, folderUid, client, folders)
 	if err != nil {
 		return nil, err
 	}
@@ -2359,8 +2931,8 @@ func NewFieldFromSchema(fieldType string, fieldData interface{}) (newField inter
 				field.PrivacyScreen = data[0].PrivacyScreen
 			}
 			if _, found := data[0].fields["value"]; found {
-				if v := GetGenericFieldSchemaValue(field.Type, data[0]); len(v) > 0 {
-					field.Value = append(field.Value, v[0].(core.Host))
+				for _, item := range GetGenericFieldSchemaValue(field.Type, data[0]) {
+					field.Value = append(field.Value, item.(core.Host))
 				}
 			}
 			return field, nil
@@ -2535,8 +3107,8 @@ func NewFieldFromSchema(fieldType string, fieldData interface{}) (newField inter
 				field.PrivacyScreen = data[0].PrivacyScreen
 			}
 			if _, found := data[0].fields["value"]; found {
-				if v := GetGenericFieldSchemaValue(field.Type, data[0]); len(v) > 0 {
-					field.Value = append(field.Value, v[0].(core.Phone))
+				for _, item := range GetGenericFieldSchemaValue(field.Type, data[0]) {
+					field.Value = append(field.Value, item.(core.Phone))
 				}
 			}
 			return field, nil
@@ -2586,8 +3158,8 @@ func NewFieldFromSchema(fieldType string, fieldData interface{}) (newField inter
 				field.PrivacyScreen = data[0].PrivacyScreen
 			}
 			if _, found := data[0].fields["value"]; found {
-				if v := GetGenericFieldSchemaValue(field.Type, data[0]); len(v) > 0 {
-					field.Value = append(field.Value, v[0].(core.SecurityQuestion))
+				for _, item := range GetGenericFieldSchemaValue(field.Type, data[0]) {
+					field.Value = append(field.Value, item.(core.SecurityQuestion))
 				}
 			}
 			return field, nil
@@ -2722,6 +3294,35 @@ func NewFieldFromSchema(fieldType string, fieldData interface{}) (newField inter
 	return nil, nil
 }
 
+// NewFieldsFromSchema parses every block of a repeatable field into its corresponding type.
+// Blocks must have distinct labels so that they can be matched back on read.
+func NewFieldsFromSchema(fieldType string, fieldData interface{}) (fields []interface{}, err error) {
+	blocks, ok := fieldData.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	labels := map[string]struct{}{}
+	for _, block := range blocks {
+		if bmap, ok := block.(map[string]interface{}); ok {
+			if label, _ := bmap["label"].(string); label != "" {
+				if _, found := labels[label]; found {
+					return nil, fmt.Errorf("duplicate label '%s' - repeated %s fields must have distinct labels", label, fieldType)
+				}
+				labels[label] = struct{}{}
+			}
+		}
+		field, err := NewFieldFromSchema(fieldType, []interface{}{block})
+		if err != nil {
+			return nil, err
+		}
+		if field != nil {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
 func NewFieldFromType(fieldType string) (field interface{}, err error) {
 	switch fieldType {
 	case "login":
@@ -2807,15 +3408,22 @@ func ParseGeneratePassword(data interface{}) (bool, error) {
 func SetFieldTypeInSchema(d *schema.ResourceData, fieldName, fieldType string) error {
 	if fieldData := d.Get(fieldName); fieldData != nil {
 		if s, ok := fieldData.([]interface{}); ok && len(s) > 0 {
-			if m, ok := s[0].(map[string]interface{}); ok {
-				if itype, ok := m["type"]; ok {
-					if _, ok := itype.(string); ok {
-						// if stype != "" && stype != fieldType { return false, errors.New("field type is already set incorrectly") }
-						m["type"] = fieldType
-						return d.Set(fieldName, fieldData)
+			// repeatable field blocks - set type on every block
+			updated := false
+			for _, item := range s {
+				if m, ok := item.(map[string]interface{}); ok {
+					if itype, ok := m["type"]; ok {
+						if _, ok := itype.(string); ok {
+							// if stype != "" && stype != fieldType { return false, errors.New("field type is already set incorrectly") }
+							m["type"] = fieldType
+							updated = true
+						}
 					}
 				}
 			}
+			if updated {
+				return d.Set(fieldName, fieldData)
+			}
 		}
 	}
 	return errors.New("failed to set field type to " + fieldType)
@@ -2893,6 +3501,12 @@ func getSchemaAndRecordFieldNames(fieldName string) (schemaName string, recordNa
 }
 
 func ApplyFieldChange(section, name string, d *schema.ResourceData, record *core.Record) (int, error) {
+	return ApplyPasswordFieldChange(section, name, d, record, nil)
+}
+
+// ApplyPasswordFieldChange is ApplyFieldChange for password fields - passwords
+// are regenerated with the password policy of the provider.
+func ApplyPasswordFieldChange(section, name string, d *schema.ResourceData, record *core.Record, policy *passwordPolicy) (int, error) {
 	modified := 0
 
 	if d == nil || record == nil {
@@ -2913,6 +3527,11 @@ func ApplyFieldChange(section, name string, d *schema.ResourceData, record *core
 		return modified, fmt.Errorf("apply change was unable to find record field name for field '%s'", name)
 	}
 
+	if repeatableFieldSchemas[schemaFieldName] {
+		// repeatable blocks are written by position - all other fields are preserved
+		return applyFieldListChange(section, recordFieldName, d.Get(schemaFieldName), record)
+	}
+
 	if fieldData, exists := d.GetOk(schemaFieldName); exists {
 		// field present in configuration
 		if fieldData != nil && len(fieldData.([]interface{})) > 0 {
@@ -2942,7 +3561,7 @@ func ApplyFieldChange(section, name string, d *schema.ResourceData, record *core
 				return modified, fmt.Errorf("apply change failed to convert schema '%s' to field '%s' from field data: '%v'", schemaFieldName, recordFieldName, fieldData)
 			} else {
 				if generate {
-					if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+					if generated, err := applyGeneratePassword(fieldData, field, policy); err != nil {
 						return modified, err
 					} else if generated {
 						if err := d.Set("password", fieldData); err != nil {
@@ -2950,11 +3569,14 @@ func ApplyFieldChange(section, name string, d *schema.ResourceData, record *core
 						}
 					}
 				}
-				// upsert
-				if record.FieldExists(section, recordFieldName) {
-					if err := record.UpdateField(section, field); err != nil {
-						return modified, err
-					}
+				// upsert - match by (type,label) so fields sharing a type don't overwrite each other
+				fieldDicts := customFieldsToDict([]interface{}{field})
+				if len(fieldDicts) == 0 {
+					return modified, fmt.Errorf("apply change failed to convert field '%s' to record format", recordFieldName)
+				}
+				label, _ := fieldDicts[0].(map[string]interface{})["label"].(string)
+				if ix := findFieldIndex(record, section, recordFieldName, label); ix >= 0 {
+					record.RecordDict[section].([]interface{})[ix] = fieldDicts[0]
 					modified++
 				} else {
 					if err := record.InsertField(section, field); err != nil {
@@ -2968,12 +3590,76 @@ func ApplyFieldChange(section, name string, d *schema.ResourceData, record *core
 		}
 	} else {
 		// field not present in configuration - remove from record if present
-		modified = record.RemoveField("fields", recordFieldName, false)
+		label := ""
+		if oldf, _ := d.GetChange(schemaFieldName); oldf != nil {
+			if s, ok := oldf.([]interface{}); ok && len(s) > 0 {
+				if fmap, ok := s[0].(map[string]interface{}); ok {
+					label, _ = fmap["label"].(string)
+				}
+			}
+		}
+		if ix := findFieldIndex(record, "fields", recordFieldName, label); ix >= 0 {
+			flds := record.RecordDict["fields"].([]interface{})
+			record.RecordDict["fields"] = append(flds[:ix:ix], flds[ix+1:]...)
+			modified++
+		}
 	}
 
 	return modified, nil
 }
 
+// findFieldIndex returns the index of the field with matching type and label in the
+// record section, falling back to the first field of that type when no label matches.
+// Returns -1 if the section has no fields of that type.
+func findFieldIndex(record *core.Record, section, fieldType, label string) int {
+	flds, ok := record.RecordDict[section].([]interface{})
+	if !ok {
+		return -1
+	}
+
+	first := -1
+	for i, fld := range flds {
+		if fmap, ok := fld.(map[string]interface{}); ok && fmt.Sprint(fmap["type"]) == fieldType {
+			if label != "" && fmap["label"] == label {
+				return i
+			}
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	return first
+}
+
+// applyFieldListChange writes repeatable field blocks by position: the n-th block
+// replaces the n-th field of that type in place, extra blocks are appended and
+// fields no longer configured are removed. Fields of other types are preserved.
+func applyFieldListChange(section, recordFieldName string, fieldData interface{}, record *core.Record) (int, error) {
+	fields, err := NewFieldsFromSchema(recordFieldName, fieldData)
+	if err != nil {
+		return 0, err
+	}
+	dicts := customFieldsToDict(fields)
+
+	current, _ := record.RecordDict[section].([]interface{})
+	updated := make([]interface{}, 0, len(current)+len(dicts))
+	written := 0
+	for _, fld := range current {
+		if fmap, ok := fld.(map[string]interface{}); ok && fmt.Sprint(fmap["type"]) == recordFieldName {
+			if written < len(dicts) {
+				updated = append(updated, dicts[written])
+				written++
+			}
+			continue
+		}
+		updated = append(updated, fld)
+	}
+	updated = append(updated, dicts[written:]...)
+	record.RecordDict[section] = updated
+
+	return len(dicts), nil
+}
+
 func mergePassword(schemaField interface{}, recordField interface{}) {
 	// password field must merge with schema to pull data not stored in record like generate=true
 	// merge schema only attributes back into the new value before schema update
@@ -2993,13 +3679,42 @@ func mergePassword(schemaField interface{}, recordField interface{}) {
 				}
 			}
 		}
+		mergePasswordComplexityOptions(schemaField, recordField)
 	}
 }
 
-func applyGeneratePassword(fieldData interface{}, field interface{}) (generated bool, e error) {
+// mergePasswordComplexityOptions copies the complexity options the record
+// doesn't store from schema to the record field.
+func mergePasswordComplexityOptions(schemaField interface{}, recordField interface{}) {
+	schemaComplexity := firstSchemaBlock(firstSchemaBlock(schemaField)["complexity"])
+	fieldMap := firstSchemaBlock(recordField)
+	if schemaComplexity == nil || fieldMap == nil {
+		return
+	}
+	complexity := firstSchemaBlock(fieldMap["complexity"])
+	if complexity == nil {
+		// counts all zero are left out of the record
+		complexity = map[string]interface{}{}
+	}
+	found := false
+	for _, key := range passwordComplexityOptions {
+		if v, ok := schemaComplexity[key]; ok {
+			complexity[key] = v
+			found = true
+		}
+	}
+	if found {
+		fieldMap["complexity"] = []interface{}{complexity}
+	}
+}
+
+// applyGeneratePassword generates the password of the field when generate is
+// set - the complexity defaults to the password policy, nil for none.
+func applyGeneratePassword(fieldData interface{}, field interface{}, policy *passwordPolicy) (generated bool, e error) {
 	if fv, ok := field.(*core.Password); ok {
-		complexity := core.PasswordComplexity{Length: 16}
-		if fv.Complexity != nil {
+		complexity := core.PasswordComplexity{Length: defaultPasswordLength}
+		options := parsePasswordOptions(fieldData)
+		if fv.Complexity != nil && options.Passphrase == nil {
 			if err := validateComplexity(fv.Complexity.Length,
 				fv.Complexity.Caps,
 				fv.Complexity.Lowercase,
@@ -3010,12 +3725,12 @@ func applyGeneratePassword(fieldData interface{}, field interface{}) (generated
 			complexity = *fv.Complexity
 		}
 		if generate, _ := ParseGeneratePassword(fieldData); generate {
-			if pwd, err := core.GeneratePassword(complexity.Length,
-				strconv.Itoa(complexity.Lowercase),
-				strconv.Itoa(complexity.Caps),
-				strconv.Itoa(complexity.Digits),
-				strconv.Itoa(complexity.Special),
-				""); err != nil {
+			options.Length = complexity.Length
+			options.Lowercase = &complexity.Lowercase
+			options.Caps = &complexity.Caps
+			options.Digits = &complexity.Digits
+			options.Special = &complexity.Special
+			if pwd, err := generatePassword(policy.applyDefaults(options)); err != nil {
 				return false, err
 			} else {
 				if len(fv.Value) > 0 {
@@ -3036,6 +3751,40 @@ func applyGeneratePassword(fieldData interface{}, field interface{}) (generated
 	return false, nil
 }
 
+// passwordComplexityOptions are the complexity options not stored in the
+// record - they only exist in configuration, like generate.
+var passwordComplexityOptions = []string{"special_characters", "exclude_characters", "exclude_ambiguous", "must_start_with_letter", "passphrase"}
+
+// parsePasswordOptions parses the complexity options of the password field
+// data that aren't stored in the record - the counts come from the record field.
+func parsePasswordOptions(data interface{}) passwordOptions {
+	options := passwordOptions{}
+	complexity := firstSchemaBlock(firstSchemaBlock(data)["complexity"])
+	options.SpecialCharacters, _ = complexity["special_characters"].(string)
+	options.ExcludeCharacters, _ = complexity["exclude_characters"].(string)
+	options.ExcludeAmbiguous, _ = complexity["exclude_ambiguous"].(bool)
+	options.MustStartWithLetter, _ = complexity["must_start_with_letter"].(bool)
+	if passphrase := firstSchemaBlock(complexity["passphrase"]); passphrase != nil {
+		options.Passphrase = &passphraseOptions{Separator: defaultPassphraseSeparator}
+		options.Passphrase.Words, _ = passphrase["words"].(int)
+		if separator, ok := passphrase["separator"].(string); ok {
+			options.Passphrase.Separator = separator
+		}
+		options.Passphrase.Capitalize, _ = passphrase["capitalize"].(bool)
+	}
+	return options
+}
+
+// firstSchemaBlock is the first block of the TypeList field data, nil when empty.
+func firstSchemaBlock(data interface{}) map[string]interface{} {
+	if s, ok := data.([]interface{}); ok && len(s) > 0 {
+		if m, ok := s[0].(map[string]interface{}); ok {
+			return m
+		}
+	}
+	return nil
+}
+
 // applyGeneratePamKey generates an SSH key pair for PAM records.
 // The private key PEM is stored in the secret field value.
 // Returns the public key string for optional storage elsewhere.
@@ -3224,7 +3973,7 @@ func isThrottled(e error) bool {
 	return false
 }
 
-func createFolderWithOptions(client core.SecretsManager, co *core.CreateOptions, folderName string, folders []*core.KeeperFolder) (uid string, e error) {
+func createFolderWithOptions(ctx context.Context, client ksmClient, co *core.CreateOptions, folderName string, folders []*core.KeeperFolder) (uid string, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3238,19 +3987,29 @@ func createFolderWithOptions(client core.SecretsManager, co *core.CreateOptions,
 		}
 	}()
 
+	call := startApiCall(client, "CreateFolder", map[string]interface{}{"shared_folder_uid": co.FolderUid, "parent_uid": co.SubFolderUid})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		uid, e = client.CreateFolder(*co, folderName, folders)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	auditTrailOf(client).folderChanged(auditFolderCreate, uid, e)
 	return uid, e
 }
 
-func getFolders(client core.SecretsManager) (folders []*core.KeeperFolder, e error) {
+func getFolders(ctx context.Context, client ksmClient) (folders []*core.KeeperFolder, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3264,19 +4023,28 @@ func getFolders(client core.SecretsManager) (folders []*core.KeeperFolder, e err
 		}
 	}()
 
+	call := startApiCall(client, "GetFolders", nil)
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		folders, e = client.GetFolders()
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
 	return folders, e
 }
 
-func getSecrets(client core.SecretsManager, uids []string) (records []*core.Record, e error) {
+func getSecrets(ctx context.Context, client ksmClient, uids []string) (records []*core.Record, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3290,19 +4058,31 @@ func getSecrets(client core.SecretsManager, uids []string) (records []*core.Reco
 		}
 	}()
 
+	call := startApiCall(client, "GetSecrets", map[string]interface{}{"record_uids": uids})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		records, e = client.GetSecrets(uids)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	if e == nil {
+		apiLogOf(client).maskRecords(records)
+	}
 	return records, e
 }
 
-func createSecretWithRecordDataUidAndOptions(client core.SecretsManager, recordUid string, createOptions *core.CreateOptions, recordData *core.RecordCreate, folders []*core.KeeperFolder) (uid string, e error) {
+func createSecretWithRecordDataUidAndOptions(ctx context.Context, client ksmClient, recordUid string, createOptions *core.CreateOptions, recordData *core.RecordCreate, folders []*core.KeeperFolder) (uid string, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3316,19 +4096,41 @@ func createSecretWithRecordDataUidAndOptions(client core.SecretsManager, recordU
 		}
 	}()
 
+	call := startApiCall(client, "CreateSecret", map[string]interface{}{"record_uid": recordUid, "record_type": recordData.RecordType})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		uid, e = client.CreateSecretWithRecordDataUidAndOptions(recordUid, createOptions, recordData, folders)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	if trail := auditTrailOf(client); trail != nil {
+		auditUid, folderUid := uid, ""
+		if auditUid == "" {
+			auditUid = recordUid
+		}
+		if createOptions != nil {
+			folderUid = createOptions.FolderUid
+			if createOptions.SubFolderUid != "" {
+				folderUid = createOptions.SubFolderUid
+			}
+		}
+		trail.recordCreated(auditUid, folderUid, recordData, e)
+	}
 	return uid, e
 }
 
-func deleteFolders(client core.SecretsManager, folderUids []string, forceDelete bool) (statuses map[string]string, e error) {
+func deleteFolders(ctx context.Context, client ksmClient, folderUids []string, forceDelete bool) (statuses map[string]string, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3342,19 +4144,37 @@ func deleteFolders(client core.SecretsManager, folderUids []string, forceDelete
 		}
 	}()
 
+	call := startApiCall(client, "DeleteFolder", map[string]interface{}{"folder_uids": folderUids, "force": forceDelete})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		statuses, e = client.DeleteFolder(folderUids, forceDelete)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	if trail := auditTrailOf(client); trail != nil {
+		for _, uid := range folderUids {
+			err := e
+			if status := statuses[uid]; err == nil && strings.ToLower(status) != "ok" {
+				err = fmt.Errorf("unexpected status: '%s'", status)
+			}
+			trail.folderChanged(auditFolderDelete, uid, err)
+		}
+	}
 	return statuses, e
 }
 
-func updateFolder(client core.SecretsManager, folderUid string, folderName string, folders []*core.KeeperFolder) (e error) {
+func updateFolder(ctx context.Context, client ksmClient, folderUid string, folderName string, folders []*core.KeeperFolder) (e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3368,19 +4188,69 @@ func updateFolder(client core.SecretsManager, folderUid string, folderName strin
 		}
 	}()
 
+	if e = writePolicyOf(client).checkFolder(ctx, folderUid, folders, client); e != nil {
+		return e
+	}
+
+	call := startApiCall(client, "UpdateFolder", map[string]interface{}{"folder_uid": folderUid})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		e = client.UpdateFolder(folderUid, folderName, folders)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
+	auditTrailOf(client).folderChanged(auditFolderUpdate, folderUid, e)
 	return e
 }
 
-func getNotation(client core.SecretsManager, notation string) (fieldValue []interface{}, e error) {
+func uploadFile(ctx context.Context, client ksmClient, record *core.Record, file *core.KeeperFileUpload) (uid string, e error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch x := r.(type) {
+			case string:
+				e = errors.New(x)
+			case error:
+				e = x
+			default:
+				e = fmt.Errorf("error in provider - uploadFile: %v", r)
+			}
+		}
+	}()
+
+	call := startApiCall(client, "UploadFile", map[string]interface{}{"record_uid": record.Uid, "size": len(file.Data)})
+	// retry after being throttled
+	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
+		uid, e = client.UploadFile(record, file)
+		if isThrottled(e) {
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
+			continue
+		}
+		break
+	}
+	call.done(e)
+	auditTrailOf(client).fileUploaded(record, e)
+	return uid, e
+}
+
+func getNotation(ctx context.Context, client ksmClient, notation string) (fieldValue []interface{}, e error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -3394,14 +4264,23 @@ func getNotation(client core.SecretsManager, notation string) (fieldValue []inte
 		}
 	}()
 
+	call := startApiCall(client, "GetNotation", map[string]interface{}{"notation": notation})
 	// retry after being throttled
 	for range MaxThrottledRetries {
+		if e = contextError(ctx); e != nil {
+			break
+		}
 		fieldValue, e = client.GetNotation(notation)
 		if isThrottled(e) {
-			time.Sleep(11 * time.Second)
+			call.throttled(throttleRetryDelay)
+			if err := retryWait(ctx); err != nil {
+				e = err
+				break
+			}
 			continue
 		}
 		break
 	}
+	call.done(e)
 	return fieldValue, e
 }
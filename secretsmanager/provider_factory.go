@@ -23,7 +23,12 @@ func ProtoV6ProviderServerFactory(ctx context.Context) (func() tfprotov6.Provide
 	}
 
 	servers := []func() tfprotov6.ProviderServer{
-		func() tfprotov6.ProviderServer { return upgradedSdkv2 },
+		func() tfprotov6.ProviderServer {
+			return readOnlyProviderServer{
+				ProviderServer: newUnmanagedFieldsProviderServer(upgradedSdkv2, sdkv2Provider.Meta),
+				meta:           sdkv2Provider.Meta,
+			}
+		},
 		providerserver.NewProtocol6(NewFWProvider()),
 	}
 
@@ -7,13 +7,14 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -28,10 +29,14 @@ var testAcc *testAccValues
 // test helpers and PreConfig hooks. It is independent of the provider lifecycle
 // so it works correctly when tests use testAccProtoV6ProviderFactories.
 var (
-	testAccKSMClient     *core.SecretsManager
+	testAccKSMClient     ksmClient
 	testAccKSMClientOnce sync.Once
 )
 
+// testAccFakeVault backs the provider when KEEPER_CREDENTIAL is not set so
+// acceptance tests run offline against an in-memory vault.
+var testAccFakeVault *fakeVault
+
 type testAccValues struct {
 	credential string
 	secrets    map[string]map[string]string
@@ -90,6 +95,37 @@ func init() {
 			"ssnCard":             {"uid": "*", "title": "tf_acc_test_ssn_card"},
 		},
 	}
+
+	if testAcc.credential == "" {
+		useFakeVault()
+	}
+}
+
+// useFakeVault points the provider and test helpers at an in-memory vault seeded
+// with fakeVaultRecords - the tf_acc_test_* records the acceptance tests expect.
+func useFakeVault() {
+	testAccFakeVault = newFakeVault()
+	testAcc.credential = fakeCredential()
+	newKsmClient = func(config core.IKeyValueStorage) ksmClient { return testAccFakeVault }
+	throttleRetryDelay = 10 * time.Millisecond
+
+	folderUid := testAccFakeVault.AddFolder("", "tf_acc_test_folder")
+	testAccFakeVault.AddFolder(folderUid, "tf_acc_test_dir")
+	for title, record := range fakeVaultRecords {
+		dict := map[string]interface{}{
+			"title":  title,
+			"notes":  title,
+			"fields": []interface{}{},
+			"custom": []interface{}{},
+		}
+		for key, value := range record {
+			dict[key] = value
+		}
+		uid := testAccFakeVault.AddRecord(folderUid, dict)
+		if dict["type"] == "file" || dict["type"] == "photo" {
+			testAccFakeVault.AddFile(uid, title+".txt", []byte(title))
+		}
+	}
 }
 
 func (testAccValues) getRecordInfo(recordType string) (uid string, title string) {
@@ -100,18 +136,27 @@ func (testAccValues) getRecordInfo(recordType string) (uid string, title string)
 			}
 		}
 	}
+	// offline the fake vault also has the records missing from the live config
+	if testAccFakeVault != nil {
+		for title, record := range fakeVaultRecords {
+			if record["type"] == recordType {
+				return "*", title
+			}
+		}
+	}
 	return "", ""
 }
 
 func (testAccValues) getTestFolder() string {
+	ctx := context.Background()
 	folderUid := strings.TrimSpace(testAcc.folderUid)
 	if folderUid == "" || folderUid == "*" {
 		creds := strings.TrimSpace(testAcc.credential)
 		if creds != "" {
 			config := core.NewMemoryKeyValueStorage(creds)
 			if config.Get(core.KEY_APP_KEY) != "" && config.Get(core.KEY_CLIENT_ID) != "" && config.Get(core.KEY_PRIVATE_KEY) != "" {
-				client := core.NewSecretsManager(&core.ClientOptions{Config: config})
-				if fuid, err := getTemplateFolder(folderUid, *client); err == nil && fuid != "" {
+				client := newKsmClient(config)
+				if fuid, err := getTemplateFolder(ctx, folderUid, client); err == nil && fuid != "" {
 					testAcc.folderUid = fuid
 				}
 			}
@@ -124,8 +169,12 @@ func (testAccValues) getTestFolder() string {
 // directly from KEEPER_CREDENTIAL. It does not depend on the Terraform
 // provider being configured, so it works correctly in tests that use
 // testAccProtoV6ProviderFactories.
-func testAccClient() *core.SecretsManager {
+func testAccClient() ksmClient {
 	testAccKSMClientOnce.Do(func() {
+		if testAccFakeVault != nil {
+			testAccKSMClient = testAccFakeVault
+			return
+		}
 		creds := strings.TrimSpace(testAcc.credential)
 		if creds == "" {
 			return
@@ -134,7 +183,7 @@ func testAccClient() *core.SecretsManager {
 		if config.Get(core.KEY_APP_KEY) == "" || config.Get(core.KEY_CLIENT_ID) == "" || config.Get(core.KEY_PRIVATE_KEY) == "" {
 			return
 		}
-		testAccKSMClient = core.NewSecretsManager(&core.ClientOptions{Config: config})
+		testAccKSMClient = newKsmClient(config)
 	})
 	return testAccKSMClient
 }
@@ -178,13 +227,14 @@ func TestValidatePamCustomFieldLabels(t *testing.T) {
 }
 
 func checkSecretExistsRemotely(uid string) resource.TestCheckFunc {
+	ctx := context.Background()
 	return func(s *terraform.State) error {
 		client := testAccClient()
 		if client == nil {
 			return fmt.Errorf("cannot create KSM client from credentials")
 		}
 
-		records, err := getSecrets(*client, []string{uid})
+		records, err := getSecrets(ctx, client, []string{uid})
 		if err != nil {
 			return err
 		}
@@ -197,13 +247,14 @@ func checkSecretExistsRemotely(uid string) resource.TestCheckFunc {
 }
 
 func checkFolderExistsRemotely(uid, name string) resource.TestCheckFunc {
+	ctx := context.Background()
 	return func(s *terraform.State) error {
 		client := testAccClient()
 		if client == nil {
 			return fmt.Errorf("cannot create KSM client from credentials")
 		}
 
-		folders, err := getFolders(*client)
+		folders, err := getFolders(ctx, client)
 		if err != nil {
 			return err
 		}
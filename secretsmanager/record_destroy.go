@@ -0,0 +1,166 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+const (
+	OnDestroyDelete  = "delete"
+	OnDestroyKeep    = "keep"
+	OnDestroyArchive = "archive"
+)
+
+// recordLifecycleAttributes only control what happens on destroy - changing
+// them doesn't update the record.
+var recordLifecycleAttributes = []string{"deletion_protection", "on_destroy"}
+
+// archivedTitlePrefix is prepended to the title of records copied to archive_folder_uid.
+const archivedTitlePrefix = "[Archived] "
+
+const archiveFolderUidDescription = "UID of the folder records with `on_destroy = \"archive\"` are copied to when destroyed. " +
+	"Can also be sourced from the `KEEPER_ARCHIVE_FOLDER_UID` environment variable."
+
+func schemaDeletionProtection() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Refuse to destroy the record until this is set to `false` and applied.",
+	}
+}
+
+func schemaOnDestroy() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      OnDestroyDelete,
+		ValidateFunc: validation.StringInSlice([]string{OnDestroyDelete, OnDestroyKeep, OnDestroyArchive}, false),
+		Description: "What happens to the record when the resource is destroyed - `delete` (default) deletes it, " +
+			"`keep` leaves it in the vault and `archive` copies it to the provider `archive_folder_uid` with the title prefixed by `" +
+			archivedTitlePrefix + "` and deletes the original. The archived copy has a new record UID - " +
+			"the shares, history and references of the original UID are not carried over.",
+	}
+}
+
+// setRecordLifecycleDefaults sets the schema defaults on import so the first plan
+// after an import has no changes.
+func setRecordLifecycleDefaults(d *schema.ResourceData) error {
+	if err := d.Set("deletion_protection", false); err != nil {
+		return err
+	}
+	return d.Set("on_destroy", OnDestroyDelete)
+}
+
+// onDestroyCustomizeDiff fails the plan of records to be archived when the
+// provider has no archive folder.
+func onDestroyCustomizeDiff(next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if provider, ok := m.(providerMeta); ok {
+			if d.Get("on_destroy").(string) == OnDestroyArchive && provider.archiveFolderUid == "" {
+				return errors.New("on_destroy = \"archive\" requires archive_folder_uid in the provider configuration")
+			}
+		}
+		if next != nil {
+			return next(ctx, d, m)
+		}
+		return nil
+	}
+}
+
+// destroyRecord removes the record of a destroyed resource as set by its
+// deletion_protection and on_destroy attributes.
+func destroyRecord(ctx context.Context, d *schema.ResourceData, uid string, provider providerMeta) error {
+	if protected, ok := d.Get("deletion_protection").(bool); ok && protected {
+		return fmt.Errorf("record UID %s has deletion_protection enabled - set deletion_protection = false and apply before destroying it", uid)
+	}
+	switch d.Get("on_destroy").(string) {
+	case OnDestroyKeep:
+		return nil
+	case OnDestroyArchive:
+		return archiveRecord(ctx, uid, provider.archiveFolderUid, provider.client)
+	default:
+		return deleteRecord(ctx, uid, provider.client)
+	}
+}
+
+// archiveRecord copies the record to the archive folder under a new UID and
+// deletes the original. The SDK can't move records, so the shares and history
+// of the original UID are lost.
+func archiveRecord(ctx context.Context, uid, archiveFolderUid string, client ksmClient) error {
+	if archiveFolderUid == "" {
+		return errors.New("on_destroy = \"archive\" requires archive_folder_uid in the provider configuration")
+	}
+	records, err := getSecrets(ctx, client, []string{uid})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		// deleteRecord reports the missing record the same way as for on_destroy = "delete"
+		return deleteRecord(ctx, uid, client)
+	}
+
+	record := records[0]
+	title := record.Title()
+	if !strings.HasPrefix(title, archivedTitlePrefix) {
+		title = archivedTitlePrefix + title
+	}
+	if _, err := copyRecord(ctx, record, archiveFolderUid, core.GenerateUid(), title, client); err != nil {
+		return fmt.Errorf("error archiving record UID %s: %w", uid, err)
+	}
+	return deleteRecord(ctx, uid, client)
+}
+
+// copyRecord creates a copy of the record with its files in the folder and
+// returns the UID of the copy. A partial copy is deleted when a file fails.
+func copyRecord(ctx context.Context, record *core.Record, folderUid, newUid, title string, client ksmClient) (string, error) {
+	recordData := core.NewRecordCreateFromJson(record.RawJson)
+	if recordData == nil {
+		return "", fmt.Errorf("error parsing record UID %s data", record.Uid)
+	}
+	recordData.Title = title
+	// the copy gets new file UIDs as the files are uploaded
+	for _, f := range recordData.Fields {
+		if fileRef, ok := f.(*core.FileRef); ok {
+			fileRef.Value = []string{}
+		}
+	}
+
+	co, err := buildCreateOptions(ctx, folderUid, client, nil)
+	if err != nil {
+		return "", err
+	}
+	newUid, err = createSecretWithRecordDataUidAndOptions(ctx, client, newUid, co, recordData, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(record.Files) == 0 {
+		return newUid, nil
+	}
+
+	copies, err := getSecrets(ctx, client, []string{newUid})
+	if err == nil && len(copies) == 0 {
+		err = fmt.Errorf("record not found - UID: %s", newUid)
+	}
+	for i := 0; err == nil && i < len(record.Files); i++ {
+		file := record.Files[i]
+		data := file.GetFileData()
+		if len(data) == 0 && file.Size > 0 {
+			err = fmt.Errorf("error downloading file '%s'", file.Name)
+			break
+		}
+		upload := &core.KeeperFileUpload{Name: file.Name, Title: file.Title, Type: file.Type, Data: data}
+		_, err = uploadFile(ctx, client, copies[0], upload)
+	}
+	if err != nil {
+		_ = deleteRecord(ctx, newUid, client)
+		return "", err
+	}
+	return newUid, nil
+}
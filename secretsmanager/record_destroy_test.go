@@ -0,0 +1,115 @@
+package secretsmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func newTestLoginData(t *testing.T, uid string, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	raw["uid"] = uid
+	d := schema.TestResourceDataRaw(t, resourceLogin().Schema, raw)
+	d.SetId(uid)
+	return d
+}
+
+func TestDestroyRecordDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	meta := providerMeta{client: vault}
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"deletion_protection": true})
+	if diags := resourceLoginDelete(ctx, d, meta); !diags.HasError() {
+		t.Error("expected an error destroying a protected record")
+	}
+	if vault.Record(uid) == nil {
+		t.Error("protected record was deleted")
+	}
+
+	// clearing the protection doesn't touch the record
+	if diags := resourceLoginRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r := resourceLogin()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"uid": uid, "title": "web", "on_destroy": OnDestroyKeep})
+	diff, err := r.Diff(ctx, d.State(), config, meta)
+	if err != nil || diff == nil {
+		t.Fatalf("plan: %v %v", diff, err)
+	}
+	state, diags := r.Apply(ctx, d.State(), diff, meta)
+	if diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+	if state.Attributes["deletion_protection"] != "false" || state.Attributes["on_destroy"] != OnDestroyKeep {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+	if vault.calls["Save"] != 0 {
+		t.Errorf("Save called %d times for a lifecycle only change", vault.calls["Save"])
+	}
+}
+
+func TestDestroyRecordKeep(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"on_destroy": OnDestroyKeep})
+	if diags := resourceLoginDelete(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if d.Id() != "" || vault.Record(uid) == nil {
+		t.Error("expected the resource removed from state and the record kept")
+	}
+}
+
+func TestDestroyRecordArchive(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	archiveUid := vault.AddFolder(folderUid, "archive")
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "password", "value": []interface{}{"s3cr3t"}}},
+	})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+	meta := providerMeta{client: vault, archiveFolderUid: archiveUid}
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"on_destroy": OnDestroyArchive})
+	if diags := resourceLoginDelete(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if vault.Record(uid) != nil {
+		t.Error("original record not deleted")
+	}
+
+	archived, err := getRecord(ctx, "*", archivedTitlePrefix+"web", vault)
+	if err != nil {
+		t.Fatalf("archived record not found: %v", err)
+	}
+	if archived.InnerFolderUid() != archiveUid || archived.GetFieldValueByType("password") != "s3cr3t" {
+		t.Errorf("unexpected archived record: folder=%q data=%s", archived.InnerFolderUid(), archived.RawJson)
+	}
+	if len(archived.Files) != 1 || string(archived.Files[0].GetFileData()) != "certificate" {
+		t.Errorf("files not archived: %v", archived.Files)
+	}
+	if refs := archived.GetFieldValuesByType("fileRef"); len(refs) != 1 || refs[0] != archived.Files[0].Uid {
+		t.Errorf("fileRef = %v, want the archived file UID", refs)
+	}
+}
+
+func TestOnDestroyArchiveRequiresFolder(t *testing.T) {
+	ctx := context.Background()
+	r := Provider().ResourcesMap["secretsmanager_login"]
+	config := map[string]interface{}{"folder_uid": "folder", "title": "web", "on_destroy": OnDestroyArchive}
+	if _, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), providerMeta{client: newFakeVault()}); err == nil {
+		t.Error("expected a plan error without archive_folder_uid")
+	}
+	meta := providerMeta{client: newFakeVault(), archiveFolderUid: "archive"}
+	if _, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), meta); err != nil {
+		t.Errorf("plan with archive_folder_uid: %v", err)
+	}
+}
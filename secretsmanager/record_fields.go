@@ -1,7 +1,9 @@
 package secretsmanager
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -435,8 +437,7 @@ func schemaEmailField() *schema.Schema {
 	return &schema.Schema{
 		Type:        schema.TypeList,
 		Optional:    true,
-		MaxItems:    1,
-		Description: "Email field data.",
+		Description: "Email field data. Repeat the block with distinct labels to store several e-mails.",
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"type": { // email
@@ -1075,49 +1076,105 @@ func schemaPasswordField(attributeName string) *schema.Schema {
 					Optional:    true,
 					Description: "Enforce generation flag.",
 				},
-				"complexity": {
+				"complexity": schemaPasswordComplexity("Password"),
+				"value": {
+					Type:          schema.TypeString,
+					Computed:      true,
+					Optional:      true,
+					Sensitive:     true,
+					ConflictsWith: []string{attributeName + ".0.generate"},
+					Description:   "Field value.",
+				},
+			},
+		},
+	}
+}
+
+// schemaPasswordComplexity is the complexity block of generated password
+// fields - secret names the generated value in the descriptions. Only the
+// counts are stored in the record, the other options live in configuration.
+func schemaPasswordComplexity(secret string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: secret + " complexity.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"length": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: secret + " length.",
+				},
+				"caps": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: "Number of uppercase characters.",
+				},
+				"lowercase": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: "Number of lowercase characters.",
+				},
+				"digits": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: "Number of digits.",
+				},
+				"special": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: "Number of special characters.",
+				},
+				"special_characters": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+					Description:  "Set of special characters to pick from, instead of the default ASCII punctuation.",
+				},
+				"exclude_characters": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Characters never to use - excluded letters and digits are replaced with others of the same class.",
+				},
+				"exclude_ambiguous": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Leave out characters that are easily mistaken for each other (`0Oo1lI|`).",
+				},
+				"must_start_with_letter": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Start the " + strings.ToLower(secret) + " with a letter.",
+				},
+				"passphrase": {
 					Type:        schema.TypeList,
 					Optional:    true,
 					MaxItems:    1,
-					Description: "Password complexity.",
+					Description: "Generate words from an embedded wordlist instead of characters - the other complexity options are ignored.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
-							"length": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Password length.",
+							"words": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Default:      defaultPassphraseWords,
+								ValidateFunc: validation.IntAtLeast(minPassphraseWords),
+								Description:  fmt.Sprintf("Number of words. Defaults to %d.", defaultPassphraseWords),
 							},
-							"caps": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of uppercase characters.",
-							},
-							"lowercase": {
-								Type:        schema.TypeInt,
+							"separator": {
+								Type:        schema.TypeString,
 								Optional:    true,
-								Description: "Number of lowercase characters.",
+								Default:     defaultPassphraseSeparator,
+								Description: fmt.Sprintf("Separator between the words. Defaults to `%s`.", defaultPassphraseSeparator),
 							},
-							"digits": {
-								Type:        schema.TypeInt,
+							"capitalize": {
+								Type:        schema.TypeBool,
 								Optional:    true,
-								Description: "Number of digits.",
-							},
-							"special": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of special characters.",
+								Description: "Capitalize the first letter of every word.",
 							},
 						},
 					},
 				},
-				"value": {
-					Type:          schema.TypeString,
-					Computed:      true,
-					Optional:      true,
-					Sensitive:     true,
-					ConflictsWith: []string{attributeName + ".0.generate"},
-					Description:   "Field value.",
-				},
 			},
 		},
 	}
@@ -1530,8 +1587,7 @@ func schemaUrlField() *schema.Schema {
 	return &schema.Schema{
 		Type:        schema.TypeList,
 		Optional:    true,
-		MaxItems:    1,
-		Description: "URL field data.",
+		Description: "URL field data. Repeat the block with distinct labels to store several URLs.",
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"type": { // url
@@ -1606,6 +1662,70 @@ func schemaCustomFieldData() *schema.Schema {
 	}
 }
 
+// customFieldJSONTypes are the custom field types whose value is jsonencode() of
+// an object or a list of objects - every other type is compared as a plain string.
+var customFieldJSONTypes = map[string]bool{
+	"phone":            true,
+	"name":             true,
+	"address":          true,
+	"paymentCard":      true,
+	"bankAccount":      true,
+	"host":             true,
+	"securityQuestion": true,
+	"keyPair":          true,
+	"script":           true,
+}
+
+// suppressEquivalentJSONItems suppresses diffs between a single JSON object and a
+// one-element JSON array holding the same object. Keeper returns a single-entry
+// complex value as an object, so value = jsonencode([{...}]) would otherwise show
+// a perpetual diff. Multi-entry values must match element by element, in order.
+// Only values of the complex types in customFieldJSONTypes are compared as JSON -
+// "1" and "1.0" of a text field are different values.
+func suppressEquivalentJSONItems(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	if oldValue == newValue {
+		return true
+	}
+	if d == nil {
+		return false
+	}
+	fieldType, _ := d.Get(strings.TrimSuffix(k, "value") + "type").(string)
+	if canonical, ok := customFieldTypeCanonical[strings.ToLower(strings.TrimSpace(fieldType))]; ok {
+		fieldType = canonical
+	}
+	if !customFieldJSONTypes[fieldType] {
+		return false
+	}
+
+	oldItems, ok := jsonObjectItems(oldValue)
+	if !ok {
+		return false
+	}
+	newItems, ok := jsonObjectItems(newValue)
+	return ok && reflect.DeepEqual(oldItems, newItems)
+}
+
+// jsonObjectItems decodes a JSON object or a list of JSON objects, as accepted by
+// parseJSONItems, and reports false for anything else.
+func jsonObjectItems(value string) ([]map[string]interface{}, bool) {
+	if strings.TrimSpace(value) == "" {
+		return nil, false
+	}
+	items, err := parseJSONItems(value)
+	if err != nil {
+		return nil, false
+	}
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		var object map[string]interface{}
+		if err := json.Unmarshal(item, &object); err != nil || object == nil {
+			return nil, false
+		}
+		objects = append(objects, object)
+	}
+	return objects, true
+}
+
 // schemaCustomField returns the schema for user-defined custom fields on a managed resource.
 // Each field has a type, label, and value. The value is always a string:
 // - Simple types (text, multiline, secret, url, email): plain string value
@@ -1654,10 +1774,11 @@ func schemaCustomField() *schema.Schema {
 					Description: "Field label. Used to identify the field within the record.",
 				},
 				"value": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "Field value. Use a plain string for simple types. Use jsonencode() for complex types (phone, name, address, paymentCard).",
+					Type:             schema.TypeString,
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: suppressEquivalentJSONItems,
+					Description:      "Field value. Use a plain string for simple types. Use jsonencode() for complex types (phone, name, address, paymentCard) - a single object or a list of objects for multi-value fields (phone, host, securityQuestion).",
 				},
 				"required": {
 					Type:        schema.TypeBool,
@@ -1675,3 +1796,14 @@ func schemaCustomField() *schema.Schema {
 		},
 	}
 }
+
+// schemaIgnoreCustomLabels returns the schema for custom field labels a managed
+// resource never reads, writes or removes (ex. fields maintained in Keeper UI).
+func schemaIgnoreCustomLabels() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Labels of custom fields Terraform should ignore. Matching fields are kept in the vault and never shown in `custom` (case-insensitive).",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
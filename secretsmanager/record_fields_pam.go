@@ -526,41 +526,7 @@ func schemaPrivateKeyPassphraseField() *schema.Schema {
 						}}
 					},
 				},
-				"complexity": {
-					Type:        schema.TypeList,
-					Optional:    true,
-					MaxItems:    1,
-					Description: "Passphrase complexity.",
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"length": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Passphrase length.",
-							},
-							"caps": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of uppercase characters.",
-							},
-							"lowercase": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of lowercase characters.",
-							},
-							"digits": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of digits.",
-							},
-							"special": {
-								Type:        schema.TypeInt,
-								Optional:    true,
-								Description: "Number of special characters.",
-							},
-						},
-					},
-				},
+				"complexity": schemaPasswordComplexity("Passphrase"),
 				"value": {
 					Type:        schema.TypeString,
 					Optional:    true,
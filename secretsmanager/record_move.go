@@ -0,0 +1,124 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// recordMoveCustomizeDiff plans folder_uid changes of records as moves. The
+// SDK can't move records - the update payload has no folder - so a moved record
+// is copied under a new UID and the uid attribute is unknown until apply, even
+// between subfolders of one shared folder. A uid set in the configuration can't
+// follow the record and fails the plan. A record already in the folder keeps
+// its UID. Changes to uid itself still replace the resource.
+func recordMoveCustomizeDiff(next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if d.Id() != "" {
+			if err := planRecordMove(ctx, d, m); err != nil {
+				return err
+			}
+		}
+		if next != nil {
+			return next(ctx, d, m)
+		}
+		return nil
+	}
+}
+
+func planRecordMove(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.HasChange("uid") {
+		return d.ForceNew("uid")
+	}
+	if !d.HasChange("folder_uid") || !d.NewValueKnown("folder_uid") {
+		return nil
+	}
+	oldFolderUid, newFolderUid := d.GetChange("folder_uid")
+	folderUid := strings.TrimSpace(newFolderUid.(string))
+	if folderUid == "" || folderUid == "*" {
+		return nil
+	}
+
+	provider, ok := m.(providerMeta)
+	if ok {
+		// a record already in the folder (ex. moved outside Terraform) keeps its UID
+		records, err := getSecrets(ctx, provider.client, []string{d.Id()})
+		if err != nil {
+			return err
+		}
+		if len(records) == 1 && recordFolderUid(records[0]) == folderUid {
+			return nil
+		}
+	}
+
+	if config := d.GetRawConfig(); !config.IsNull() && !config.GetAttr("uid").IsNull() {
+		return fmt.Errorf("record UID %s can't move to folder %s while uid is set in the configuration - "+
+			"moved records get a new UID, remove uid from the configuration to move the record", d.Id(), folderUid)
+	}
+
+	if ok {
+		folders, err := getFolders(ctx, provider.client)
+		if err != nil {
+			return err
+		}
+		if _, err := getSharedFolder(ctx, folderUid, provider.client, folders); err != nil {
+			return fmt.Errorf("record UID %s can't move to folder %s: %w", d.Id(), folderUid, err)
+		}
+		policy := writePolicyOf(provider.client)
+		for _, uid := range []string{strings.TrimSpace(oldFolderUid.(string)), folderUid} {
+			if uid == "" {
+				continue
+			}
+			if err := policy.checkFolder(ctx, uid, folders, provider.client); err != nil {
+				return fmt.Errorf("record UID %s can't move to folder %s: %w", d.Id(), folderUid, err)
+			}
+		}
+	}
+	return d.SetNewComputed("uid")
+}
+
+// updatedRecordUid returns the UID of the record being updated - the planned
+// uid of a record moving to another folder is unknown.
+func updatedRecordUid(d *schema.ResourceData) string {
+	uid, _ := d.GetChange("uid")
+	return strings.TrimSpace(uid.(string))
+}
+
+// saveOrMoveRecord saves the updated record, or moves it when folder_uid
+// changed, and returns the UID of the record.
+func saveOrMoveRecord(ctx context.Context, d *schema.ResourceData, record *core.Record, client ksmClient) (string, error) {
+	folderUid := strings.TrimSpace(d.Get("folder_uid").(string))
+	if !d.HasChange("folder_uid") || folderUid == "" || folderUid == "*" || recordFolderUid(record) == folderUid {
+		return record.Uid, saveRecord(ctx, record, client)
+	}
+
+	uid, err := moveRecord(ctx, record, folderUid, client)
+	if uid != "" {
+		d.SetId(uid)
+		if e := d.Set("uid", uid); e != nil && err == nil {
+			err = e
+		}
+	}
+	return uid, err
+}
+
+// moveRecord moves the record with its changes to the folder and returns its
+// new UID. The SDK can't move records so the record is copied (files included)
+// and the original deleted.
+func moveRecord(ctx context.Context, record *core.Record, folderUid string, client ksmClient) (string, error) {
+	if err := writePolicyOf(client).checkRecord(ctx, record, client); err != nil {
+		return "", err
+	}
+	uid, err := copyRecord(ctx, record, folderUid, core.GenerateUid(), record.Title(), client)
+	if err != nil {
+		return "", fmt.Errorf("error moving record UID %s to folder %s: %w", record.Uid, folderUid, err)
+	}
+	if err := deleteRecord(ctx, record.Uid, client); err != nil {
+		return uid, fmt.Errorf("record UID %s was copied to folder %s as record UID %s but the original wasn't deleted: %w",
+			record.Uid, folderUid, uid, err)
+	}
+	return uid, nil
+}
@@ -0,0 +1,130 @@
+package secretsmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestRecordMove(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	targetUid := vault.AddFolder(folderUid, "team")
+	uid := vault.AddRecord(folderUid, map[string]interface{}{
+		"type":  "login",
+		"title": "web",
+		"notes": "keep me",
+	})
+	vault.AddFile(uid, "cert.pem", []byte("certificate"))
+	meta := providerMeta{client: vault}
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"folder_uid": folderUid})
+	if diags := resourceLoginRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	r := Provider().ResourcesMap["secretsmanager_login"]
+	config := map[string]interface{}{"folder_uid": targetUid, "title": "moved", "notes": "keep me"}
+	diff, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil || diff == nil {
+		t.Fatalf("plan: %v %v", diff, err)
+	}
+	if diff.RequiresNew() || !diff.Attributes["uid"].NewComputed {
+		t.Fatalf("expected an in-place update with an unknown uid: %v", diff)
+	}
+	state, diags := r.Apply(ctx, d.State(), diff, meta)
+	if diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+
+	newUid := state.Attributes["uid"]
+	if newUid == "" || newUid == uid || state.ID != newUid {
+		t.Fatalf("unexpected uid after move: %v", state.Attributes)
+	}
+	if vault.Record(uid) != nil {
+		t.Error("original record not deleted")
+	}
+	moved, err := getRecord(ctx, newUid, "", vault)
+	if err != nil {
+		t.Fatalf("moved record not found: %v", err)
+	}
+	if moved.InnerFolderUid() != targetUid || moved.Title() != "moved" || moved.Notes() != "keep me" {
+		t.Errorf("unexpected moved record: folder=%q data=%s", moved.InnerFolderUid(), moved.RawJson)
+	}
+	if len(moved.Files) != 1 || string(moved.Files[0].GetFileData()) != "certificate" {
+		t.Errorf("files not moved: %v", moved.Files)
+	}
+}
+
+func TestRecordMovePlan(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	otherUid := vault.AddFolder("", "production")
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	meta := providerMeta{client: vault}
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"folder_uid": folderUid})
+	if diags := resourceLoginRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r := Provider().ResourcesMap["secretsmanager_login"]
+
+	config := map[string]interface{}{"folder_uid": "missing", "title": "web"}
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta); err == nil {
+		t.Error("expected a plan error moving to an unknown folder")
+	}
+
+	guarded := providerMeta{client: configureWritePolicy(vault, writePolicy{AllowedFolders: []string{folderUid}})}
+	config = map[string]interface{}{"folder_uid": otherUid, "title": "web"}
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), guarded); err == nil {
+		t.Error("expected a plan error moving outside allowed_write_folders")
+	}
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta); err != nil {
+		t.Errorf("plan move to another shared folder: %v", err)
+	}
+
+	// a changed uid still replaces the resource
+	config = map[string]interface{}{"uid": "bmV3LXJlY29yZC11aWQ", "folder_uid": folderUid, "title": "web"}
+	diff, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil || diff == nil || !diff.RequiresNew() {
+		t.Errorf("expected a uid change to replace the resource: %v %v", diff, err)
+	}
+}
+
+// TestRecordMoveAlreadyInFolder plans and applies a folder_uid change of a record
+// already moved to the folder outside Terraform - the record keeps its UID.
+func TestRecordMoveAlreadyInFolder(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	targetUid := vault.AddFolder(folderUid, "team")
+	uid := vault.AddRecord(targetUid, map[string]interface{}{"type": "login", "title": "web"})
+	meta := providerMeta{client: vault}
+
+	d := newTestLoginData(t, uid, map[string]interface{}{"folder_uid": targetUid})
+	if diags := resourceLoginRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	state := d.State()
+	state.Attributes["folder_uid"] = folderUid
+
+	r := Provider().ResourcesMap["secretsmanager_login"]
+	config := map[string]interface{}{"uid": uid, "folder_uid": targetUid, "title": "renamed"}
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil || diff == nil {
+		t.Fatalf("plan: %v %v", diff, err)
+	}
+	if diff.RequiresNew() || diff.Attributes["uid"] != nil && diff.Attributes["uid"].NewComputed {
+		t.Fatalf("expected an update keeping the uid: %v", diff)
+	}
+	state, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+	if state.ID != uid || state.Attributes["folder_uid"] != targetUid {
+		t.Errorf("unexpected state after apply: %v", state.Attributes)
+	}
+	if record := vault.Record(uid); record == nil || vault.calls["CreateSecretWithRecordDataUidAndOptions"] != 0 {
+		t.Errorf("record was copied: %v, %d creates", record, vault.calls["CreateSecretWithRecordDataUidAndOptions"])
+	}
+}
@@ -26,11 +26,10 @@ func resourceAddress() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions.",
+				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -55,14 +54,17 @@ func resourceAddress() *schema.Resource {
 			"address":  schemaAddressField(),
 			"file_ref": schemaFileRefField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourceAddressCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -117,13 +119,13 @@ func resourceAddressCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
-	uid, err := createRecord(uid, folderUid, nrc, client)
+	uid, err := createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -146,7 +148,7 @@ func resourceAddressCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourceAddressRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -155,7 +157,7 @@ func resourceAddressRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			// resource does not exist in the vault
@@ -204,9 +206,9 @@ func resourceAddressRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.FromErr(err)
 	}
 
-	customItems := getFieldItemsData(secret.RecordDict, "custom")
-	if err := d.Set("custom", customItems); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields)...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -215,22 +217,28 @@ func resourceAddressRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 func resourceAddressUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	// folderUid := strings.TrimSpace(d.Get("folder_uid").(string))
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return diags
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -255,16 +263,13 @@ func resourceAddressUpdate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	if d.HasChange("custom") {
-		customData := d.Get("custom").([]interface{})
-		fields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields); err != nil {
 			return diag.FromErr(err)
 		}
-		secret.RecordDict["custom"] = customFieldsToDict(fields)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if uid, err = saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -274,7 +279,6 @@ func resourceAddressUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourceAddressDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -282,7 +286,7 @@ func resourceAddressDelete(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			// record UID no longer exists - probably deleted externally
 			diags = append(diags, diag.Diagnostic{
@@ -310,8 +314,11 @@ func resourceAddressImport(ctx context.Context, d *schema.ResourceData, m interf
 	if err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourceAddressRead(ctx, d, m)
+	diags := resourceAddressRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for i := range diags {
 			if diags[i].Severity == diag.Error {
@@ -1,11 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -123,6 +124,7 @@ func TestAccResourceAddress_update(t *testing.T) {
 }
 
 func TestAccResourceAddress_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretType := "address"
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
@@ -150,8 +152,8 @@ func TestAccResourceAddress_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -26,11 +26,10 @@ func resourceBankAccount() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions.",
+				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -61,14 +60,17 @@ func resourceBankAccount() *schema.Resource {
 			"totp":         schemaOneTimeCodeField(),
 			"file_ref":     schemaFileRefField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourceBankAccountCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -126,7 +128,7 @@ func resourceBankAccountCreate(ctx context.Context, d *schema.ResourceData, m in
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("password", fieldData); err != nil {
@@ -140,10 +142,10 @@ func resourceBankAccountCreate(ctx context.Context, d *schema.ResourceData, m in
 		}
 	}
 	if fieldData := d.Get("url"); fieldData != nil && len(fieldData.([]interface{})) > 0 {
-		if field, err := NewFieldFromSchema("url", fieldData); err != nil {
+		if fields, err := NewFieldsFromSchema("url", fieldData); err != nil {
 			return diag.FromErr(err)
-		} else if field != nil {
-			nrc.Fields = append(nrc.Fields, field)
+		} else if len(fields) > 0 {
+			nrc.Fields = append(nrc.Fields, fields...)
 			if err := SetFieldTypeInSchema(d, "url", "url"); err != nil {
 				return diag.FromErr(err)
 			}
@@ -190,13 +192,13 @@ func resourceBankAccountCreate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
-	uid, err := createRecord(uid, folderUid, nrc, client)
+	uid, err := createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -219,7 +221,7 @@ func resourceBankAccountCreate(ctx context.Context, d *schema.ResourceData, m in
 
 func resourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -228,7 +230,7 @@ func resourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			// resource does not exist in the vault
@@ -284,7 +286,7 @@ func resourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m inte
 	if err = d.Set("password", password); err != nil {
 		return diag.FromErr(err)
 	}
-	url := getFieldResourceData("url", "fields", secret)
+	url := getFieldsResourceData("url", "fields", secret, d.Get("url"))
 	if err = d.Set("url", url); err != nil {
 		return diag.FromErr(err)
 	}
@@ -302,9 +304,9 @@ func resourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.FromErr(err)
 	}
 
-	customItems := getFieldItemsData(secret.RecordDict, "custom")
-	if err := d.Set("custom", customItems); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields)...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -313,22 +315,28 @@ func resourceBankAccountRead(ctx context.Context, d *schema.ResourceData, m inte
 
 func resourceBankAccountUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	// folderUid := strings.TrimSpace(d.Get("folder_uid").(string))
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return diags
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -356,7 +364,7 @@ func resourceBankAccountUpdate(ctx context.Context, d *schema.ResourceData, m in
 		}
 	}
 	if d.HasChange("password") {
-		if _, err := ApplyFieldChange("fields", "password", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("fields", "password", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -383,16 +391,13 @@ func resourceBankAccountUpdate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if d.HasChange("custom") {
-		customData := d.Get("custom").([]interface{})
-		fields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields); err != nil {
 			return diag.FromErr(err)
 		}
-		secret.RecordDict["custom"] = customFieldsToDict(fields)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if uid, err = saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -402,7 +407,6 @@ func resourceBankAccountUpdate(ctx context.Context, d *schema.ResourceData, m in
 
 func resourceBankAccountDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -410,7 +414,7 @@ func resourceBankAccountDelete(ctx context.Context, d *schema.ResourceData, m in
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			// record UID no longer exists - probably deleted externally
 			diags = append(diags, diag.Diagnostic{
@@ -438,8 +442,11 @@ func resourceBankAccountImport(ctx context.Context, d *schema.ResourceData, m in
 	if err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourceBankAccountRead(ctx, d, m)
+	diags := resourceBankAccountRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for i := range diags {
 			if diags[i].Severity == diag.Error {
@@ -1,11 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -170,6 +171,7 @@ func TestAccResourceBankAccount_update(t *testing.T) {
 }
 
 func TestAccResourceBankAccount_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretType := "bankAccount"
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
@@ -197,8 +199,8 @@ func TestAccResourceBankAccount_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -26,11 +26,10 @@ func resourceContact() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions.",
+				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -59,14 +58,17 @@ func resourceContact() *schema.Resource {
 			"address_ref": schemaAddressRefField(),
 			"file_ref":    schemaFileRefField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourceContactCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -111,10 +113,10 @@ func resourceContactCreate(ctx context.Context, d *schema.ResourceData, m interf
 		}
 	}
 	if fieldData := d.Get("email"); fieldData != nil && len(fieldData.([]interface{})) > 0 {
-		if field, err := NewFieldFromSchema("email", fieldData); err != nil {
+		if fields, err := NewFieldsFromSchema("email", fieldData); err != nil {
 			return diag.FromErr(err)
-		} else if field != nil {
-			nrc.Fields = append(nrc.Fields, field)
+		} else if len(fields) > 0 {
+			nrc.Fields = append(nrc.Fields, fields...)
 			if err := SetFieldTypeInSchema(d, "email", "email"); err != nil {
 				return diag.FromErr(err)
 			}
@@ -161,13 +163,13 @@ func resourceContactCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
-	uid, err := createRecord(uid, folderUid, nrc, client)
+	uid, err := createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -190,7 +192,7 @@ func resourceContactCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourceContactRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -199,7 +201,7 @@ func resourceContactRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			// resource does not exist in the vault
@@ -242,11 +244,11 @@ func resourceContactRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if err = d.Set("name", name); err != nil {
 		return diag.FromErr(err)
 	}
-	company := getFieldResourceData("text", "fields", secret)
+	company := getLabeledFieldResourceData("text", "fields", secret, d.Get("company"))
 	if err = d.Set("company", company); err != nil {
 		return diag.FromErr(err)
 	}
-	email := getFieldResourceData("email", "fields", secret)
+	email := getFieldsResourceData("email", "fields", secret, d.Get("email"))
 	if err = d.Set("email", email); err != nil {
 		return diag.FromErr(err)
 	}
@@ -264,9 +266,9 @@ func resourceContactRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.FromErr(err)
 	}
 
-	customItems := getFieldItemsData(secret.RecordDict, "custom")
-	if err := d.Set("custom", customItems); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields)...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -275,22 +277,28 @@ func resourceContactRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 func resourceContactUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	// folderUid := strings.TrimSpace(d.Get("folder_uid").(string))
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return diags
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -335,16 +343,13 @@ func resourceContactUpdate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	if d.HasChange("custom") {
-		customData := d.Get("custom").([]interface{})
-		fields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields); err != nil {
 			return diag.FromErr(err)
 		}
-		secret.RecordDict["custom"] = customFieldsToDict(fields)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if uid, err = saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -354,7 +359,6 @@ func resourceContactUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourceContactDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -362,7 +366,7 @@ func resourceContactDelete(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			// record UID no longer exists - probably deleted externally
 			diags = append(diags, diag.Diagnostic{
@@ -390,8 +394,11 @@ func resourceContactImport(ctx context.Context, d *schema.ResourceData, m interf
 	if err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourceContactRead(ctx, d, m)
+	diags := resourceContactRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for i := range diags {
 			if diags[i].Severity == diag.Error {
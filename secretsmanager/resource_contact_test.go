@@ -1,11 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -149,6 +150,7 @@ func TestAccResourceContact_update(t *testing.T) {
 }
 
 func TestAccResourceContact_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretType := "contact"
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
@@ -176,8 +178,8 @@ func TestAccResourceContact_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -26,11 +26,10 @@ func resourceDatabaseCredentials() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions.",
+				Description:  "The UID of the folder where the secret is stored. The folder or its parent shared folder must be accessible to your KSM application with 'Can Edit' permissions. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -58,14 +57,17 @@ func resourceDatabaseCredentials() *schema.Resource {
 			"host":     schemaHostField(),
 			"file_ref": schemaFileRefField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourceDatabaseCredentialsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -113,7 +115,7 @@ func resourceDatabaseCredentialsCreate(ctx context.Context, d *schema.ResourceDa
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("password", fieldData); err != nil {
@@ -157,13 +159,13 @@ func resourceDatabaseCredentialsCreate(ctx context.Context, d *schema.ResourceDa
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
-	uid, err := createRecord(uid, folderUid, nrc, client)
+	uid, err := createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -186,7 +188,7 @@ func resourceDatabaseCredentialsCreate(ctx context.Context, d *schema.ResourceDa
 
 func resourceDatabaseCredentialsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -195,7 +197,7 @@ func resourceDatabaseCredentialsRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			// resource does not exist in the vault
@@ -234,7 +236,7 @@ func resourceDatabaseCredentialsRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
-	dbType := getFieldResourceData("text", "fields", secret)
+	dbType := getLabeledFieldResourceData("text", "fields", secret, d.Get("db_type"))
 	if err = d.Set("db_type", dbType); err != nil {
 		return diag.FromErr(err)
 	}
@@ -257,9 +259,9 @@ func resourceDatabaseCredentialsRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
-	customItems := getFieldItemsData(secret.RecordDict, "custom")
-	if err := d.Set("custom", customItems); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields)...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -268,22 +270,28 @@ func resourceDatabaseCredentialsRead(ctx context.Context, d *schema.ResourceData
 
 func resourceDatabaseCredentialsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	// folderUid := strings.TrimSpace(d.Get("folder_uid").(string))
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return diags
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -306,7 +314,7 @@ func resourceDatabaseCredentialsUpdate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 	if d.HasChange("password") {
-		if _, err := ApplyFieldChange("fields", "password", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("fields", "password", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -323,16 +331,13 @@ func resourceDatabaseCredentialsUpdate(ctx context.Context, d *schema.ResourceDa
 	}
 
 	if d.HasChange("custom") {
-		customData := d.Get("custom").([]interface{})
-		fields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields); err != nil {
 			return diag.FromErr(err)
 		}
-		secret.RecordDict["custom"] = customFieldsToDict(fields)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if uid, err = saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -342,7 +347,6 @@ func resourceDatabaseCredentialsUpdate(ctx context.Context, d *schema.ResourceDa
 
 func resourceDatabaseCredentialsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -350,7 +354,7 @@ func resourceDatabaseCredentialsDelete(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			// record UID no longer exists - probably deleted externally
 			diags = append(diags, diag.Diagnostic{
@@ -378,8 +382,11 @@ func resourceDatabaseCredentialsImport(ctx context.Context, d *schema.ResourceDa
 	if err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourceDatabaseCredentialsRead(ctx, d, m)
+	diags := resourceDatabaseCredentialsRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for i := range diags {
 			if diags[i].Severity == diag.Error {
@@ -47,7 +47,7 @@ func resourceFolder() *schema.Resource {
 
 func resourceFolderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	parentFolderUid := strings.TrimSpace(d.Get("parent_uid").(string))
@@ -64,7 +64,7 @@ func resourceFolderCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 
 	// folderUid := strings.TrimSpace(d.Get("uid").(string))
-	folderUid, err := createFolder(parentFolderUid, folderName, client)
+	folderUid, err := createFolder(ctx, parentFolderUid, folderName, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -79,7 +79,7 @@ func resourceFolderCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 func resourceFolderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	parentFolderUid := strings.TrimSpace(d.Get("parent_uid").(string))
@@ -96,7 +96,7 @@ func resourceFolderRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("folder UID and/or name required to locate the folder")
 	}
 
-	folders, err := findSubFolder(parentFolderUid, folderUid, folderName, client)
+	folders, err := findSubFolder(ctx, parentFolderUid, folderUid, folderName, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -127,7 +127,7 @@ func resourceFolderRead(ctx context.Context, d *schema.ResourceData, m interface
 
 func resourceFolderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	parentFolderUid := strings.TrimSpace(d.Get("parent_uid").(string))
@@ -146,7 +146,7 @@ func resourceFolderUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	folderName := strings.TrimSpace(d.Get("name").(string))
 	if d.HasChange("name") {
-		if err := updateFolder(client, folderUid, folderName, nil); err != nil {
+		if err := updateFolder(ctx, client, folderUid, folderName, nil); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -157,7 +157,7 @@ func resourceFolderUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 
 func resourceFolderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	folderUid := strings.TrimSpace(d.Get("uid").(string))
@@ -169,7 +169,7 @@ func resourceFolderDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	if !ok {
 		forceDelete = false
 	}
-	if err := deleteFolder(folderUid, forceDelete, client); err != nil {
+	if err := deleteFolder(ctx, folderUid, forceDelete, client); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			// record UID no longer exists - probably deleted externally
 			diags = append(diags, diag.Diagnostic{
@@ -192,7 +192,7 @@ func resourceFolderDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 func resourceFolderImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 
 	uid := d.Id()
 	err := d.Set("uid", uid)
@@ -200,7 +200,7 @@ func resourceFolderImport(ctx context.Context, d *schema.ResourceData, m interfa
 		return nil, err
 	}
 
-	folders, err := findSubFolder("", uid, "", client)
+	folders, err := findSubFolder(ctx, "", uid, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +221,7 @@ func resourceFolderImport(ctx context.Context, d *schema.ResourceData, m interfa
 		return nil, err
 	}
 
-	diags := resourceFolderRead(ctx, d, m)
+	diags := resourceFolderRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for i := range diags {
 			if diags[i].Severity == diag.Error {
@@ -1,12 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccResourceFolder_create(t *testing.T) {
@@ -89,6 +89,7 @@ func TestAccResourceFolder_update(t *testing.T) {
 }
 
 func TestAccResourceFolder_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	testFolderUid := getTestFolderUid()
 	if testFolderUid == "" {
 		t.Skip("Skipping test - TF_ACC not set or test folder not configured")
@@ -110,12 +111,12 @@ func TestAccResourceFolder_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					folders, err := findFolder("", "", secretTitle, client)
+					client := testAccClient()
+					folders, err := findFolder(ctx, "", "", secretTitle, client)
 					if err != nil || len(folders) == 0 {
 						t.Skip("Skipping test - TF_ACC not set or test folder not configured")
 					}
-					if err := deleteFolder(folders[0].FolderUid, true, client); err != nil {
+					if err := deleteFolder(ctx, folders[0].FolderUid, true, client); err != nil {
 						t.Skip("Skipping test - TF_ACC not set or test folder not configured")
 					}
 				},
@@ -155,12 +156,12 @@ func TestAccResourceFolder_import(t *testing.T) {
 			// {
 			// 	PreConfig: func() {
 			// 		// Delete folder outside of Terraform workspace
-			// 		client := *testAccClient()
-			// 		folders, err := findFolder(testFolderUid, "", secretTitle, client)
+			// 		client := testAccClient()
+			// 		folders, err := findFolder(ctx, testFolderUid, "", secretTitle, client)
 			// 		if err != nil || len(folders) == 0 {
 			// 			t.Skip("Skipping test - TF_ACC not set or test folder not configured")
 			// 		}
-			// 		if err := deleteFolder(folders[0].FolderUid, true, client); err != nil {
+			// 		if err := deleteFolder(ctx, folders[0].FolderUid, true, client); err != nil {
 			// 			t.Skip("Skipping test - TF_ACC not set or test folder not configured")
 			// 		}
 			// 	},
@@ -172,14 +173,15 @@ func TestAccResourceFolder_import(t *testing.T) {
 }
 
 func getTestFolderUid() string {
-	accProvider, d := getConfiguredProvider(os.Getenv(envCredential))
+	ctx := context.Background()
+	accProvider, d := getConfiguredProvider(testAcc.credential)
 	if d.HasError() {
 		return ""
 	}
 
 	testFolderName := "tf_acc_test_dir"
-	client := *accProvider.client
-	folders, err := findFolder("", "", testFolderName, client)
+	client := accProvider.client
+	folders, err := findFolder(ctx, "", "", testFolderName, client)
 	if err != nil || len(folders) == 0 {
 		return ""
 	}
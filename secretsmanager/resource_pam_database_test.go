@@ -1,11 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -176,6 +177,7 @@ func TestAccResourcePamDatabase_update(t *testing.T) {
 }
 
 func TestAccResourcePamDatabase_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
 	secretTitle := "tf_acc_test_pam_database_delete"
@@ -201,8 +203,8 @@ func TestAccResourcePamDatabase_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -1,11 +1,12 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -202,6 +203,7 @@ func TestAccResourcePamDirectory_update(t *testing.T) {
 }
 
 func TestAccResourcePamDirectory_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
 	secretTitle := "tf_acc_test_pam_directory_delete"
@@ -227,8 +229,8 @@ func TestAccResourcePamDirectory_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -27,11 +27,10 @@ func resourcePamMachine() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The folder UID where the secret is stored. The parent shared folder must be non empty.",
+				Description:  "The folder UID where the secret is stored. The parent shared folder must be non empty. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -69,14 +68,17 @@ func resourcePamMachine() *schema.Resource {
 			"file_ref":               schemaFileRefField(),
 			"totp":                   schemaOneTimeCodeField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourcePamMachineCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	var err error
@@ -134,7 +136,7 @@ func resourcePamMachineCreate(ctx context.Context, d *schema.ResourceData, m int
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("password", fieldData); err != nil {
@@ -154,7 +156,7 @@ func resourcePamMachineCreate(ctx context.Context, d *schema.ResourceData, m int
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("private_key_passphrase", fieldData); err != nil {
@@ -312,14 +314,14 @@ func resourcePamMachineCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
 
-	uid, err = createRecord(uid, folderUid, nrc, client)
+	uid, err = createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -342,7 +344,7 @@ func resourcePamMachineCreate(ctx context.Context, d *schema.ResourceData, m int
 
 func resourcePamMachineRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -351,7 +353,7 @@ func resourcePamMachineRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			d.SetId("")
@@ -465,18 +467,9 @@ func resourcePamMachineRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	// "Private Key Passphrase" is a platform-managed entry in the custom section.
 	// Exclude it from user-visible state to prevent a perpetual diff.
-	allCustom := getFieldItemsData(secret.RecordDict, "custom")
-	userCustom := make([]interface{}, 0, len(allCustom))
-	for _, item := range allCustom {
-		if m, ok := item.(map[string]interface{}); ok {
-			if label, _ := m["label"].(string); strings.EqualFold(label, "Private Key Passphrase") {
-				continue
-			}
-		}
-		userCustom = append(userCustom, item)
-	}
-	if err := d.Set("custom", userCustom); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields, "Private Key Passphrase")...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -485,20 +478,26 @@ func resourcePamMachineRead(ctx context.Context, d *schema.ResourceData, m inter
 
 func resourcePamMachineUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return nil
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -516,12 +515,12 @@ func resourcePamMachineUpdate(ctx context.Context, d *schema.ResourceData, m int
 		}
 	}
 	if d.HasChange("password") {
-		if _, err := ApplyFieldChange("fields", "password", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("fields", "password", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 	if d.HasChange("private_key_passphrase") {
-		if _, err := ApplyFieldChange("custom", "private_key_passphrase", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("custom", "private_key_passphrase", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -661,27 +660,14 @@ func resourcePamMachineUpdate(ctx context.Context, d *schema.ResourceData, m int
 		if err := validatePamCustomFieldLabels(customData, "pam_machine"); err != nil {
 			return diag.FromErr(err)
 		}
-		userFields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		// Preserve "Private Key Passphrase" from the current vault state.
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields, "Private Key Passphrase"); err != nil {
 			return diag.FromErr(err)
 		}
-		// Preserve "Private Key Passphrase" from the current vault state;
-		// replace all other custom entries with the user-defined fields.
-		var preserved []interface{}
-		if current, ok := secret.RecordDict["custom"].([]interface{}); ok {
-			for _, item := range current {
-				if m, ok := item.(map[string]interface{}); ok {
-					if label, _ := m["label"].(string); strings.EqualFold(label, "Private Key Passphrase") {
-						preserved = append(preserved, item)
-					}
-				}
-			}
-		}
-		secret.RecordDict["custom"] = append(preserved, customFieldsToDict(userFields)...)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if _, err := saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -690,7 +676,6 @@ func resourcePamMachineUpdate(ctx context.Context, d *schema.ResourceData, m int
 
 func resourcePamMachineDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -698,7 +683,7 @@ func resourcePamMachineDelete(ctx context.Context, d *schema.ResourceData, m int
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Warning,
@@ -725,8 +710,11 @@ func resourcePamMachineImport(ctx context.Context, d *schema.ResourceData, m int
 	if err := d.Set("uid", uid); err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourcePamMachineRead(ctx, d, m)
+	diags := resourcePamMachineRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for _, d := range diags {
 			if d.Severity == diag.Error {
@@ -1,12 +1,13 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -206,6 +207,7 @@ func TestAccResourcePamMachine_update(t *testing.T) {
 }
 
 func TestAccResourcePamMachine_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
 	secretTitle := "tf_acc_test_pam_machine_delete"
@@ -231,8 +233,8 @@ func TestAccResourcePamMachine_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
@@ -26,11 +26,10 @@ func resourcePamUser() *schema.Resource {
 				Computed:     true,
 				Optional:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
-				Description:  "The folder UID where the secret is stored. The parent shared folder must be non empty.",
+				Description:  "The folder UID where the secret is stored. The parent shared folder must be non empty. Changing it copies the record to the new folder under a new UID and deletes the original - Keeper Secrets Manager can't move records.",
 			},
 			"uid": {
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Optional:     true,
 				Computed:     true,
 				AtLeastOneOf: []string{"folder_uid", "uid"},
@@ -63,14 +62,17 @@ func resourcePamUser() *schema.Resource {
 			"file_ref":               schemaFileRefField(),
 			"totp":                   schemaOneTimeCodeField(),
 			// custom[]
-			"custom": schemaCustomField(),
+			"custom":               schemaCustomField(),
+			"ignore_custom_labels": schemaIgnoreCustomLabels(),
+			"deletion_protection":  schemaDeletionProtection(),
+			"on_destroy":           schemaOnDestroy(),
 		},
 	}
 }
 
 func resourcePamUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -108,7 +110,7 @@ func resourcePamUserCreate(ctx context.Context, d *schema.ResourceData, m interf
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("password", fieldData); err != nil {
@@ -129,7 +131,7 @@ func resourcePamUserCreate(ctx context.Context, d *schema.ResourceData, m interf
 		if field, err := NewFieldFromSchema("password", fieldData); err != nil {
 			return diag.FromErr(err)
 		} else if field != nil {
-			if generated, err := applyGeneratePassword(fieldData, field); err != nil {
+			if generated, err := applyGeneratePassword(fieldData, field, provider.passwordPolicy); err != nil {
 				return diag.FromErr(err)
 			} else if generated {
 				if err := d.Set("private_key_passphrase", fieldData); err != nil {
@@ -259,13 +261,13 @@ func resourcePamUserCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	if folderUid == "*" {
-		if fuid, err := getTemplateFolder(folderUid, client); err != nil {
+		if fuid, err := getTemplateFolder(ctx, folderUid, client); err != nil {
 			return diag.FromErr(err)
 		} else {
 			folderUid = fuid
 		}
 	}
-	uid, err := createRecord(uid, folderUid, nrc, client)
+	uid, err := createRecord(ctx, uid, folderUid, nrc, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -288,7 +290,7 @@ func resourcePamUserCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourcePamUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -297,7 +299,7 @@ func resourcePamUserRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("record UID and/or title required to locate the record")
 	}
 
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "record not found") {
 			d.SetId("")
@@ -388,18 +390,9 @@ func resourcePamUserRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	// "Private Key Passphrase" is a platform-managed entry in the custom section.
 	// Exclude it from user-visible state to prevent a perpetual diff.
-	allCustom := getFieldItemsData(secret.RecordDict, "custom")
-	userCustom := make([]interface{}, 0, len(allCustom))
-	for _, item := range allCustom {
-		if m, ok := item.(map[string]interface{}); ok {
-			if label, _ := m["label"].(string); strings.EqualFold(label, "Private Key Passphrase") {
-				continue
-			}
-		}
-		userCustom = append(userCustom, item)
-	}
-	if err := d.Set("custom", userCustom); err != nil {
-		return diag.FromErr(err)
+	diags = append(diags, setCustomResourceData(ctx, d, secret, provider.unmanagedFields, "Private Key Passphrase")...)
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(uid)
@@ -408,21 +401,27 @@ func resourcePamUserRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 func resourcePamUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
+	client := provider.client
 	var diags diag.Diagnostics
 
-	uid := strings.TrimSpace(d.Get("uid").(string))
+	uid := updatedRecordUid(d)
 	if uid == "" {
 		return diag.Errorf("'uid' is required to update existing resource")
 	}
 
-	hasRestrictedChanges := d.HasChange("folder_uid") || d.HasChange("uid") || d.HasChange("type")
+	if !d.HasChangesExcept(recordLifecycleAttributes...) {
+		// deletion_protection and on_destroy are kept in the state only
+		return diags
+	}
+
+	// folder_uid changes move the record and leave its planned uid unknown
+	hasRestrictedChanges := (d.HasChange("uid") && !d.HasChange("folder_uid")) || d.HasChange("type")
 	if hasRestrictedChanges {
-		return diag.Errorf("changes to folder_uid, uid, and type are not allowed")
+		return diag.Errorf("changes to uid and type are not allowed")
 	}
 
 	title := strings.TrimSpace(d.Get("title").(string))
-	secret, err := getRecord(uid, title, client)
+	secret, err := getRecord(ctx, uid, title, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -440,13 +439,13 @@ func resourcePamUserUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		}
 	}
 	if d.HasChange("password") {
-		if _, err := ApplyFieldChange("fields", "password", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("fields", "password", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 	// Handle private key passphrase (custom field)
 	if d.HasChange("private_key_passphrase") {
-		if _, err := ApplyFieldChange("custom", "private_key_passphrase", d, secret); err != nil {
+		if _, err := ApplyPasswordFieldChange("custom", "private_key_passphrase", d, secret, provider.passwordPolicy); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -532,27 +531,14 @@ func resourcePamUserUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		if err := validatePamCustomFieldLabels(customData, "pam_user"); err != nil {
 			return diag.FromErr(err)
 		}
-		userFields, err := customFieldsFromSchema(customData)
-		if err != nil {
+		// Preserve "Private Key Passphrase" from the current vault state.
+		if err := applyCustomFieldsChange(d, secret, provider.unmanagedFields, "Private Key Passphrase"); err != nil {
 			return diag.FromErr(err)
 		}
-		// Preserve "Private Key Passphrase" from the current vault state;
-		// replace all other custom entries with the user-defined fields.
-		var preserved []interface{}
-		if current, ok := secret.RecordDict["custom"].([]interface{}); ok {
-			for _, item := range current {
-				if m, ok := item.(map[string]interface{}); ok {
-					if label, _ := m["label"].(string); strings.EqualFold(label, "Private Key Passphrase") {
-						preserved = append(preserved, item)
-					}
-				}
-			}
-		}
-		secret.RecordDict["custom"] = append(preserved, customFieldsToDict(userFields)...)
 	}
 
 	secret.RawJson = core.DictToJson(secret.RecordDict)
-	if err := saveRecord(secret, client); err != nil {
+	if uid, err = saveOrMoveRecord(ctx, d, secret, client); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -562,7 +548,6 @@ func resourcePamUserUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 func resourcePamUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	provider := m.(providerMeta)
-	client := *provider.client
 	var diags diag.Diagnostics
 
 	uid := strings.TrimSpace(d.Get("uid").(string))
@@ -570,7 +555,7 @@ func resourcePamUserDelete(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("'uid' is required to delete existing resource")
 	}
 
-	if err := deleteRecord(uid, client); err != nil {
+	if err := destroyRecord(ctx, d, uid, provider); err != nil {
 		if strings.HasSuffix(err.Error(), "unexpected status: ''") {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Warning,
@@ -598,8 +583,11 @@ func resourcePamUserImport(ctx context.Context, d *schema.ResourceData, m interf
 	if err := d.Set("uid", uid); err != nil {
 		return nil, err
 	}
+	if err := setRecordLifecycleDefaults(d); err != nil {
+		return nil, err
+	}
 
-	diags := resourcePamUserRead(ctx, d, m)
+	diags := resourcePamUserRead(withRecordImport(ctx), d, m)
 	if diags.HasError() {
 		for _, d := range diags {
 			if d.Severity == diag.Error {
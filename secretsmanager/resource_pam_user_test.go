@@ -1,12 +1,13 @@
 package secretsmanager
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/keeper-security/secrets-manager-go/core"
 )
 
@@ -150,6 +151,7 @@ func TestAccResourcePamUser_update(t *testing.T) {
 }
 
 func TestAccResourcePamUser_deleteDetection(t *testing.T) {
+	ctx := context.Background()
 	secretFolderUid := testAcc.getTestFolder()
 	secretUid := core.GenerateUid()
 	secretTitle := "tf_acc_test_pam_user_delete"
@@ -175,8 +177,8 @@ func TestAccResourcePamUser_deleteDetection(t *testing.T) {
 			{
 				PreConfig: func() {
 					// Delete secret outside of Terraform workspace
-					client := *testAccClient()
-					if err := deleteRecord(secretUid, client); err != nil {
+					client := testAccClient()
+					if err := deleteRecord(ctx, secretUid, client); err != nil {
 						t.Fail()
 					}
 				},
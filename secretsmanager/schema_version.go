@@ -0,0 +1,58 @@
+package secretsmanager
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// recordSchemaVersion is the state schema version of the record resources.
+// Every change to the schema that existing state can't be read with as is
+// (renamed or removed attributes, reshaped nested blocks, new attributes whose
+// default must be written to state) bumps the version and appends an
+// upgrader from the previous version to recordStateUpgraders - with a golden
+// state fixture of the new version under testdata/state.
+//
+//	0 - initial schema
+//	1 - deletion_protection and on_destroy, whose defaults are missing from
+//	    older state, and ignore_custom_labels
+const recordSchemaVersion = 1
+
+// recordStateUpgraders returns the upgraders from every earlier schema
+// version of the record resource name to recordSchemaVersion. Earlier
+// versions are decoded with their frozen schemas - recordSchemasV0 - never with
+// a schema derived from the current resource.
+func recordStateUpgraders(name string) []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    recordSchemasV0[name],
+			Upgrade: upgradeRecordStateV0,
+		},
+	}
+}
+
+// upgradeRecordStateV0 writes the deletion_protection and on_destroy defaults
+// to state so the first plan after upgrading the provider has no changes -
+// the same as setRecordLifecycleDefaults on import.
+func upgradeRecordStateV0(_ context.Context, state map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	if state["deletion_protection"] == nil {
+		state["deletion_protection"] = false
+	}
+	if state["on_destroy"] == nil {
+		state["on_destroy"] = OnDestroyDelete
+	}
+	return state, nil
+}
+
+// setSchemaVersion versions the state schema of the managed resource. The
+// folder resource schema hasn't changed so it stays at version 0.
+func setSchemaVersion(name string, r *schema.Resource) {
+	if _, found := r.Schema["on_destroy"]; found {
+		r.SchemaVersion = recordSchemaVersion
+		r.StateUpgraders = recordStateUpgraders(name)
+	}
+}
@@ -0,0 +1,98 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var updateStateFixtures = flag.Bool("update-state-fixtures", false, "write the upgraded state fixtures of testdata/state")
+
+// stateFixturePath is the golden state of the resource at the schema version.
+func stateFixturePath(resource string, version int) string {
+	return filepath.Join("testdata", "state", resource, fmt.Sprintf("v%d.json", version))
+}
+
+func readStateFixture(t *testing.T, resource string, version int) map[string]interface{} {
+	t.Helper()
+	content, err := os.ReadFile(stateFixturePath(resource, version))
+	if err != nil {
+		t.Fatalf("read state fixture: %v", err)
+	}
+	state := map[string]interface{}{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		t.Fatalf("invalid state fixture %s: %v", stateFixturePath(resource, version), err)
+	}
+	return state
+}
+
+func writeStateFixture(t *testing.T, resource string, version int, state map[string]interface{}) {
+	t.Helper()
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		t.Fatalf("encode state fixture: %v", err)
+	}
+	if err := os.WriteFile(stateFixturePath(resource, version), append(content, '\n'), 0644); err != nil {
+		t.Fatalf("write state fixture: %v", err)
+	}
+}
+
+// TestStateUpgradeFixtures upgrades the golden state of every schema version
+// to the next one and checks the latest decodes with the current schema - a
+// schema change older state can't be read with fails here until the version
+// is bumped with an upgrader. Run with -update-state-fixtures to write the
+// fixtures of a new version.
+func TestStateUpgradeFixtures(t *testing.T) {
+	ctx := context.Background()
+	for name, r := range Provider().ResourcesMap {
+		t.Run(name, func(t *testing.T) {
+			state := readStateFixture(t, name, 0)
+			for version := 0; version < r.SchemaVersion; version++ {
+				upgrader := r.StateUpgraders[version]
+				if upgrader.Version != version {
+					t.Fatalf("upgrader %d is for version %d", version, upgrader.Version)
+				}
+				content, _ := json.Marshal(state)
+				if _, err := ctyjson.Unmarshal(content, upgrader.Type); err != nil {
+					t.Fatalf("fixture v%d doesn't match the schema of version %d: %v", version, version, err)
+				}
+				upgraded, err := upgrader.Upgrade(ctx, state, providerMeta{})
+				if err != nil {
+					t.Fatalf("upgrade from version %d: %v", version, err)
+				}
+				if *updateStateFixtures {
+					writeStateFixture(t, name, version+1, upgraded)
+				}
+				// compare as read back from JSON
+				content, _ = json.Marshal(upgraded)
+				state = map[string]interface{}{}
+				_ = json.Unmarshal(content, &state)
+				if want := readStateFixture(t, name, version+1); !reflect.DeepEqual(state, want) {
+					t.Fatalf("upgrade from version %d:\n got %v\nwant %v", version, state, want)
+				}
+			}
+			if _, err := schema.JSONMapToStateValue(state, r.CoreConfigSchema()); err != nil {
+				t.Errorf("fixture v%d doesn't match the current schema - bump the schema version with an upgrader: %v", r.SchemaVersion, err)
+			}
+		})
+	}
+}
+
+func TestUpgradeRecordStateV0(t *testing.T) {
+	state, err := upgradeRecordStateV0(context.Background(), map[string]interface{}{"title": "web", "on_destroy": OnDestroyKeep}, nil)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	want := map[string]interface{}{"title": "web", "deletion_protection": false, "on_destroy": OnDestroyKeep}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("state = %v, want %v", state, want)
+	}
+}
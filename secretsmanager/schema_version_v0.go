@@ -0,0 +1,528 @@
+package secretsmanager
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+)
+
+// recordSchemasV0 are the state schemas of the record resources at schema
+// version 0 - frozen copies of the schemas before schema versioning, not derived
+// from the current resources. Never edit them: state written by the provider
+// before version 1 is decoded with these types.
+var recordSchemasV0 = map[string]cty.Type{
+	"secretsmanager_address": cty.Object(map[string]cty.Type{
+		"address": cty.List(cty.Object(map[string]cty.Type{
+			"label":          cty.String,
+			"privacy_screen": cty.Bool,
+			"required":       cty.Bool,
+			"type":           cty.String,
+			"value": cty.List(cty.Object(map[string]cty.Type{
+				"city":    cty.String,
+				"country": cty.String,
+				"state":   cty.String,
+				"street1": cty.String,
+				"street2": cty.String,
+				"zip":     cty.String,
+			})),
+		})),
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"notes":      cty.String,
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_bank_account": cty.Object(map[string]cty.Type{
+		"bank_account": cty.List(cty.Object(map[string]cty.Type{
+			"label":          cty.String,
+			"privacy_screen": cty.Bool,
+			"required":       cty.Bool,
+			"type":           cty.String,
+			"value": cty.List(cty.Object(map[string]cty.Type{
+				"account_number": cty.String,
+				"account_type":   cty.String,
+				"other_type":     cty.String,
+				"routing_number": cty.String,
+			})),
+		})),
+		"card_ref":   cty.List(recordFieldV0),
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"login":      cty.List(recordFieldV0),
+		"name":       cty.List(recordNameV0),
+		"notes":      cty.String,
+		"password":   cty.List(recordPasswordV0),
+		"title":      cty.String,
+		"totp":       cty.List(recordFieldV0),
+		"type":       cty.String,
+		"uid":        cty.String,
+		"url":        cty.List(recordFieldV0),
+	}),
+	"secretsmanager_bank_card": cty.Object(map[string]cty.Type{
+		"address_ref":     cty.List(recordFieldV0),
+		"cardholder_name": cty.List(recordFieldV0),
+		"custom":          cty.List(recordFieldV0),
+		"file_ref":        cty.List(recordFileRefV0),
+		"folder_uid":      cty.String,
+		"id":              cty.String,
+		"notes":           cty.String,
+		"payment_card": cty.List(cty.Object(map[string]cty.Type{
+			"label":          cty.String,
+			"privacy_screen": cty.Bool,
+			"required":       cty.Bool,
+			"type":           cty.String,
+			"value": cty.List(cty.Object(map[string]cty.Type{
+				"card_expiration_date": cty.String,
+				"card_number":          cty.String,
+				"card_security_code":   cty.String,
+			})),
+		})),
+		"pin_code": cty.List(recordFieldV0),
+		"title":    cty.String,
+		"type":     cty.String,
+		"uid":      cty.String,
+	}),
+	"secretsmanager_birth_certificate": cty.Object(map[string]cty.Type{
+		"birth_date": cty.List(recordDateV0),
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"name":       cty.List(recordNameV0),
+		"notes":      cty.String,
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_contact": cty.Object(map[string]cty.Type{
+		"address_ref": cty.List(recordFieldV0),
+		"company":     cty.List(recordFieldV0),
+		"custom":      cty.List(recordFieldV0),
+		"email":       cty.List(recordFieldV0),
+		"file_ref":    cty.List(recordFileRefV0),
+		"folder_uid":  cty.String,
+		"id":          cty.String,
+		"name":        cty.List(recordNameV0),
+		"notes":       cty.String,
+		"phone": cty.List(cty.Object(map[string]cty.Type{
+			"label":          cty.String,
+			"privacy_screen": cty.Bool,
+			"required":       cty.Bool,
+			"type":           cty.String,
+			"value": cty.List(cty.Object(map[string]cty.Type{
+				"ext":    cty.String,
+				"number": cty.String,
+				"region": cty.String,
+				"type":   cty.String,
+			})),
+		})),
+		"title": cty.String,
+		"type":  cty.String,
+		"uid":   cty.String,
+	}),
+	"secretsmanager_database_credentials": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"db_type":    cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"host":       cty.List(recordHostV0),
+		"id":         cty.String,
+		"login":      cty.List(recordFieldV0),
+		"notes":      cty.String,
+		"password":   cty.List(recordPasswordV0),
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_driver_license": cty.Object(map[string]cty.Type{
+		"address_ref":           cty.List(recordFieldV0),
+		"birth_date":            cty.List(recordDateV0),
+		"custom":                cty.List(recordFieldV0),
+		"driver_license_number": cty.List(recordFieldV0),
+		"expiration_date":       cty.List(recordDateV0),
+		"file_ref":              cty.List(recordFileRefV0),
+		"folder_uid":            cty.String,
+		"id":                    cty.String,
+		"name":                  cty.List(recordNameV0),
+		"notes":                 cty.String,
+		"title":                 cty.String,
+		"type":                  cty.String,
+		"uid":                   cty.String,
+	}),
+	"secretsmanager_encrypted_notes": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"date":       cty.List(recordDateV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"note":       cty.List(recordFieldV0),
+		"notes":      cty.String,
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_file": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"notes":      cty.String,
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_health_insurance": cty.Object(map[string]cty.Type{
+		"account_number": cty.List(recordFieldV0),
+		"custom":         cty.List(recordFieldV0),
+		"file_ref":       cty.List(recordFileRefV0),
+		"folder_uid":     cty.String,
+		"id":             cty.String,
+		"login":          cty.List(recordFieldV0),
+		"name":           cty.List(recordNameV0),
+		"notes":          cty.String,
+		"password":       cty.List(recordPasswordV0),
+		"title":          cty.String,
+		"type":           cty.String,
+		"uid":            cty.String,
+		"url":            cty.List(recordFieldV0),
+	}),
+	"secretsmanager_login": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"login":      cty.List(recordFieldV0),
+		"notes":      cty.String,
+		"password":   cty.List(recordPasswordV0),
+		"title":      cty.String,
+		"totp":       cty.List(recordFieldV0),
+		"type":       cty.String,
+		"uid":        cty.String,
+		"url":        cty.List(recordFieldV0),
+	}),
+	"secretsmanager_membership": cty.Object(map[string]cty.Type{
+		"account_number": cty.List(recordFieldV0),
+		"custom":         cty.List(recordFieldV0),
+		"file_ref":       cty.List(recordFileRefV0),
+		"folder_uid":     cty.String,
+		"id":             cty.String,
+		"name":           cty.List(recordNameV0),
+		"notes":          cty.String,
+		"password":       cty.List(recordPasswordV0),
+		"title":          cty.String,
+		"type":           cty.String,
+		"uid":            cty.String,
+	}),
+	"secretsmanager_pam_database": cty.Object(map[string]cty.Type{
+		"custom":           cty.List(recordFieldV0),
+		"database_id":      cty.List(recordFieldV0),
+		"database_type":    cty.String,
+		"file_ref":         cty.List(recordFileRefV0),
+		"folder_uid":       cty.String,
+		"id":               cty.String,
+		"notes":            cty.String,
+		"pam_hostname":     cty.List(recordPamHostnameV0),
+		"pam_settings":     cty.String,
+		"provider_group":   cty.List(recordFieldV0),
+		"provider_region":  cty.List(recordFieldV0),
+		"rotation_scripts": cty.List(recordScriptV0),
+		"title":            cty.String,
+		"totp":             cty.List(recordFieldV0),
+		"type":             cty.String,
+		"uid":              cty.String,
+		"use_ssl":          cty.List(recordCheckboxV0),
+	}),
+	"secretsmanager_pam_directory": cty.Object(map[string]cty.Type{
+		"alternative_ips":    cty.List(recordFieldV0),
+		"custom":             cty.List(recordFieldV0),
+		"directory_id":       cty.List(recordFieldV0),
+		"directory_type":     cty.String,
+		"distinguished_name": cty.List(recordFieldV0),
+		"domain_name":        cty.List(recordFieldV0),
+		"file_ref":           cty.List(recordFileRefV0),
+		"folder_uid":         cty.String,
+		"id":                 cty.String,
+		"notes":              cty.String,
+		"pam_hostname":       cty.List(recordPamHostnameV0),
+		"pam_settings":       cty.String,
+		"provider_group":     cty.List(recordFieldV0),
+		"provider_region":    cty.List(recordFieldV0),
+		"rotation_scripts":   cty.List(recordScriptV0),
+		"title":              cty.String,
+		"totp":               cty.List(recordFieldV0),
+		"type":               cty.String,
+		"uid":                cty.String,
+		"use_ssl":            cty.List(recordCheckboxV0),
+		"user_match":         cty.List(recordFieldV0),
+	}),
+	"secretsmanager_pam_machine": cty.Object(map[string]cty.Type{
+		"custom":                 cty.List(recordFieldV0),
+		"file_ref":               cty.List(recordFileRefV0),
+		"folder_uid":             cty.String,
+		"id":                     cty.String,
+		"instance_id":            cty.List(recordFieldV0),
+		"instance_name":          cty.List(recordFieldV0),
+		"login":                  cty.List(recordFieldV0),
+		"notes":                  cty.String,
+		"operating_system":       cty.List(recordFieldV0),
+		"pam_hostname":           cty.List(recordPamHostnameV0),
+		"pam_settings":           cty.String,
+		"password":               cty.List(recordPasswordV0),
+		"private_key_passphrase": cty.List(recordPrivateKeyPassphraseV0),
+		"private_pem_key":        cty.List(recordPrivatePemKeyV0),
+		"provider_group":         cty.List(recordFieldV0),
+		"provider_region":        cty.List(recordFieldV0),
+		"rotation_scripts":       cty.List(recordScriptV0),
+		"ssl_verification":       cty.List(recordCheckboxV0),
+		"title":                  cty.String,
+		"totp":                   cty.List(recordFieldV0),
+		"type":                   cty.String,
+		"uid":                    cty.String,
+	}),
+	"secretsmanager_pam_remote_browser": cty.Object(map[string]cty.Type{
+		"custom":                      cty.List(recordFieldV0),
+		"file_ref":                    cty.List(recordFileRefV0),
+		"folder_uid":                  cty.String,
+		"id":                          cty.String,
+		"notes":                       cty.String,
+		"pam_remote_browser_settings": cty.String,
+		"rbi_url":                     cty.List(recordFieldV0),
+		"title":                       cty.String,
+		"totp":                        cty.List(recordFieldV0),
+		"traffic_encryption_seed":     cty.List(recordFieldV0),
+		"type":                        cty.String,
+		"uid":                         cty.String,
+	}),
+	"secretsmanager_pam_user": cty.Object(map[string]cty.Type{
+		"connect_database":       cty.List(recordFieldV0),
+		"custom":                 cty.List(recordFieldV0),
+		"distinguished_name":     cty.List(recordFieldV0),
+		"file_ref":               cty.List(recordFileRefV0),
+		"folder_uid":             cty.String,
+		"id":                     cty.String,
+		"login":                  cty.List(recordFieldV0),
+		"managed":                cty.List(recordCheckboxV0),
+		"notes":                  cty.String,
+		"password":               cty.List(recordPasswordV0),
+		"private_key_passphrase": cty.List(recordPrivateKeyPassphraseV0),
+		"private_pem_key":        cty.List(recordPrivatePemKeyV0),
+		"rotation_scripts":       cty.List(recordScriptV0),
+		"title":                  cty.String,
+		"totp":                   cty.List(recordFieldV0),
+		"type":                   cty.String,
+		"uid":                    cty.String,
+	}),
+	"secretsmanager_passport": cty.Object(map[string]cty.Type{
+		"address_ref":     cty.List(recordFieldV0),
+		"birth_date":      cty.List(recordDateV0),
+		"custom":          cty.List(recordFieldV0),
+		"date_issued":     cty.List(recordDateV0),
+		"expiration_date": cty.List(recordDateV0),
+		"file_ref":        cty.List(recordFileRefV0),
+		"folder_uid":      cty.String,
+		"id":              cty.String,
+		"name":            cty.List(recordNameV0),
+		"notes":           cty.String,
+		"passport_number": cty.List(recordFieldV0),
+		"password":        cty.List(recordPasswordV0),
+		"title":           cty.String,
+		"type":            cty.String,
+		"uid":             cty.String,
+	}),
+	"secretsmanager_photo": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"id":         cty.String,
+		"notes":      cty.String,
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_server_credentials": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"host":       cty.List(recordHostV0),
+		"id":         cty.String,
+		"login":      cty.List(recordFieldV0),
+		"notes":      cty.String,
+		"password":   cty.List(recordPasswordV0),
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_software_license": cty.Object(map[string]cty.Type{
+		"activation_date": cty.List(recordDateV0),
+		"custom":          cty.List(recordFieldV0),
+		"expiration_date": cty.List(recordDateV0),
+		"file_ref":        cty.List(recordFileRefV0),
+		"folder_uid":      cty.String,
+		"id":              cty.String,
+		"license_number":  cty.List(recordFieldV0),
+		"notes":           cty.String,
+		"title":           cty.String,
+		"type":            cty.String,
+		"uid":             cty.String,
+	}),
+	"secretsmanager_ssh_keys": cty.Object(map[string]cty.Type{
+		"custom":     cty.List(recordFieldV0),
+		"file_ref":   cty.List(recordFileRefV0),
+		"folder_uid": cty.String,
+		"host":       cty.List(recordHostV0),
+		"id":         cty.String,
+		"key_pair": cty.List(cty.Object(map[string]cty.Type{
+			"generate":       cty.String,
+			"key_bits":       cty.Number,
+			"key_type":       cty.String,
+			"label":          cty.String,
+			"privacy_screen": cty.Bool,
+			"required":       cty.Bool,
+			"type":           cty.String,
+			"value": cty.List(cty.Object(map[string]cty.Type{
+				"private_key": cty.String,
+				"public_key":  cty.String,
+			})),
+		})),
+		"login":      cty.List(recordFieldV0),
+		"notes":      cty.String,
+		"passphrase": cty.List(recordPasswordV0),
+		"title":      cty.String,
+		"type":       cty.String,
+		"uid":        cty.String,
+	}),
+	"secretsmanager_ssn_card": cty.Object(map[string]cty.Type{
+		"custom":          cty.List(recordFieldV0),
+		"file_ref":        cty.List(recordFileRefV0),
+		"folder_uid":      cty.String,
+		"id":              cty.String,
+		"identity_number": cty.List(recordFieldV0),
+		"name":            cty.List(recordNameV0),
+		"notes":           cty.String,
+		"title":           cty.String,
+		"type":            cty.String,
+		"uid":             cty.String,
+	}),
+}
+
+// The field blocks shared by the version 0 record schemas.
+var (
+	recordCheckboxV0 = cty.Object(map[string]cty.Type{
+		"label":    cty.String,
+		"required": cty.Bool,
+		"type":     cty.String,
+		"value":    cty.Bool,
+	})
+	recordDateV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value":          cty.Number,
+	})
+	recordFieldV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value":          cty.String,
+	})
+	recordFileRefV0 = cty.Object(map[string]cty.Type{
+		"label":    cty.String,
+		"required": cty.Bool,
+		"type":     cty.String,
+		"value": cty.List(cty.Object(map[string]cty.Type{
+			"content_base64": cty.String,
+			"last_modified":  cty.String,
+			"name":           cty.String,
+			"size":           cty.Number,
+			"title":          cty.String,
+			"type":           cty.String,
+			"uid":            cty.String,
+		})),
+	})
+	recordHostV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value": cty.List(cty.Object(map[string]cty.Type{
+			"host_name": cty.String,
+			"port":      cty.String,
+		})),
+	})
+	recordNameV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value": cty.List(cty.Object(map[string]cty.Type{
+			"first":  cty.String,
+			"last":   cty.String,
+			"middle": cty.String,
+		})),
+	})
+	recordPamHostnameV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value": cty.List(cty.Object(map[string]cty.Type{
+			"hostname": cty.String,
+			"port":     cty.String,
+		})),
+	})
+	recordPasswordV0 = cty.Object(map[string]cty.Type{
+		"complexity": cty.List(cty.Object(map[string]cty.Type{
+			"caps":      cty.Number,
+			"digits":    cty.Number,
+			"length":    cty.Number,
+			"lowercase": cty.Number,
+			"special":   cty.Number,
+		})),
+		"enforce_generation": cty.Bool,
+		"generate":           cty.String,
+		"label":              cty.String,
+		"privacy_screen":     cty.Bool,
+		"required":           cty.Bool,
+		"type":               cty.String,
+		"value":              cty.String,
+	})
+	recordPrivateKeyPassphraseV0 = cty.Object(map[string]cty.Type{
+		"complexity": cty.List(cty.Object(map[string]cty.Type{
+			"caps":      cty.Number,
+			"digits":    cty.Number,
+			"length":    cty.Number,
+			"lowercase": cty.Number,
+			"special":   cty.Number,
+		})),
+		"generate": cty.String,
+		"type":     cty.String,
+		"value":    cty.String,
+	})
+	recordPrivatePemKeyV0 = cty.Object(map[string]cty.Type{
+		"generate":       cty.String,
+		"key_bits":       cty.Number,
+		"key_type":       cty.String,
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"public_key":     cty.String,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value":          cty.String,
+	})
+	recordScriptV0 = cty.Object(map[string]cty.Type{
+		"label":          cty.String,
+		"privacy_screen": cty.Bool,
+		"required":       cty.Bool,
+		"type":           cty.String,
+		"value": cty.List(cty.Object(map[string]cty.Type{
+			"command":    cty.String,
+			"file_ref":   cty.String,
+			"record_ref": cty.List(cty.String),
+		})),
+	})
+)
@@ -0,0 +1,262 @@
+package secretsmanager
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// Provider connection settings - both providers read them with the same
+// environment variable fallbacks.
+const (
+	hostnameDescription = "Keeper server region (`US`, `EU`, `AU`, `GOV`, `JP`, `CA`), hostname (ex. `keepersecurity.eu`) " +
+		"or `https://` URL with an optional port (ex. `https://ksm.example.com:8443`). Overrides the hostname stored in `credential`. " +
+		"Can also be sourced from the `KEEPER_HOSTNAME` environment variable."
+	proxyUrlDescription = "URL of the HTTP(S) proxy used to reach Keeper (ex. `http://proxy.example.com:3128`). " +
+		"Defaults to the `HTTPS_PROXY` and `NO_PROXY` environment variables. Can also be sourced from the `KEEPER_PROXY_URL` environment variable."
+	caCertFileDescription = "Path to a PEM file with additional CA certificates to trust (ex. a TLS inspecting proxy CA). " +
+		"Can also be sourced from the `KEEPER_CA_CERT_FILE` environment variable."
+	caCertPemDescription = "PEM encoded additional CA certificates to trust. " +
+		"Can also be sourced from the `KEEPER_CA_CERT_PEM` environment variable."
+	insecureSkipVerifyDescription = "Skip TLS certificate verification - for testing only. " +
+		"Can also be sourced from the `KEEPER_INSECURE_SKIP_VERIFY` environment variable."
+)
+
+// keeperRegions maps region codes to Keeper server hostnames.
+var keeperRegions = map[string]string{
+	"US":  "keepersecurity.com",
+	"EU":  "keepersecurity.eu",
+	"AU":  "keepersecurity.com.au",
+	"GOV": "govcloud.keepersecurity.us",
+	"JP":  "keepersecurity.jp",
+	"CA":  "keepersecurity.ca",
+}
+
+// networkSettings are the provider connection settings. The SDK sends requests
+// through http.DefaultClient, so proxy and TLS settings are applied per Keeper
+// host by defaultKsmTransport - never to the transport of other requests.
+type networkSettings struct {
+	Hostname           string
+	ProxyUrl           string
+	CaCertFile         string
+	CaCertPem          string
+	InsecureSkipVerify bool
+}
+
+// withEnvDefaults fills unset values from the environment.
+func (n networkSettings) withEnvDefaults() networkSettings {
+	if n.Hostname == "" {
+		n.Hostname = envDefault("KEEPER_HOSTNAME")
+	}
+	if n.ProxyUrl == "" {
+		n.ProxyUrl = envDefault("KEEPER_PROXY_URL")
+	}
+	if n.CaCertFile == "" {
+		n.CaCertFile = envDefault("KEEPER_CA_CERT_FILE")
+	}
+	if n.CaCertPem == "" {
+		n.CaCertPem = envDefault("KEEPER_CA_CERT_PEM")
+	}
+	if !n.InsecureSkipVerify {
+		n.InsecureSkipVerify, _ = core.StrToBool(envDefault("KEEPER_INSECURE_SKIP_VERIFY"))
+	}
+	return n
+}
+
+// configureNetwork applies the connection settings to the KSM config and routes
+// the requests to its Keeper host through a transport with the proxy and TLS
+// settings. A configuration without settings resets the route of its host.
+func configureNetwork(config core.IKeyValueStorage, settings networkSettings) error {
+	if err := applyHostname(config, settings.Hostname); err != nil {
+		return err
+	}
+	transport, err := newBaseTransport(settings)
+	if err != nil {
+		return err
+	}
+	installKsmTransport()
+	defaultKsmTransport.setHostTransport(core.GetServerHostname("", config), transport)
+	return nil
+}
+
+// newBaseTransport returns the transport for the proxy and TLS settings, nil
+// when none are set.
+func newBaseTransport(settings networkSettings) (*http.Transport, error) {
+	proxyUrl := strings.TrimSpace(settings.ProxyUrl)
+	caCertFile := strings.TrimSpace(settings.CaCertFile)
+	caCertPem := strings.TrimSpace(settings.CaCertPem)
+	if proxyUrl == "" && caCertFile == "" && caCertPem == "" && !settings.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyUrl != "" {
+		u, err := url.Parse(proxyUrl)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy_url '%s' - expected an URL like http://proxy.example.com:3128", proxyUrl)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy_url '%s' - unsupported scheme '%s'", proxyUrl, u.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caCertFile != "" || caCertPem != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if caCertFile != "" {
+			pem, err := os.ReadFile(caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading ca_cert_file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in ca_cert_file '%s'", caCertFile)
+			}
+		}
+		if caCertPem != "" && !pool.AppendCertsFromPEM([]byte(caCertPem)) {
+			return nil, fmt.Errorf("no PEM certificates found in ca_cert_pem")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	tlsConfig.InsecureSkipVerify = settings.InsecureSkipVerify
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// ksmTransport carries Secrets Manager API requests and file downloads - the SDK
+// sends both through http.DefaultClient. The SDK keeps only the host name from the
+// configured hostname and always connects to port 443, so requests for a host
+// configured with a port are redirected to it here.
+//
+// Requests to a configured Keeper host use the transport of its provider
+// configuration. File transfers go to storage hosts no configuration owns -
+// they use the transport of the Keeper host when only one is configured in
+// the process and base otherwise, so settings never leak between aliases.
+type ksmTransport struct {
+	mu        sync.RWMutex
+	base      http.RoundTripper
+	redirects map[string]string // host name -> host:port
+	// hosts are the configured Keeper hosts - host name -> transport, nil
+	// for base
+	hosts map[string]http.RoundTripper
+}
+
+var defaultKsmTransport = &ksmTransport{redirects: map[string]string{}, hosts: map[string]http.RoundTripper{}}
+
+var installKsmTransportOnce sync.Once
+
+// installKsmTransport makes http.DefaultClient use defaultKsmTransport, wrapping
+// the transport it used before.
+func installKsmTransport() {
+	installKsmTransportOnce.Do(func() {
+		defaultKsmTransport.setBase(http.DefaultClient.Transport)
+		http.DefaultClient.Transport = defaultKsmTransport
+	})
+}
+
+// setBase replaces the transport requests are sent with and returns the previous one.
+func (t *ksmTransport) setBase(base http.RoundTripper) http.RoundTripper {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	previous := t.base
+	t.base = base
+	return previous
+}
+
+// setHostTransport routes the requests to the Keeper host through transport -
+// base when nil.
+func (t *ksmTransport) setHostTransport(hostname string, transport *http.Transport) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if transport == nil {
+		t.hosts[hostname] = nil
+		return
+	}
+	t.hosts[hostname] = transport
+}
+
+// transportFor returns the transport of requests to the host - the caller
+// holds the read lock.
+func (t *ksmTransport) transportFor(hostname string) http.RoundTripper {
+	transport, found := t.hosts[hostname]
+	if !found && len(t.hosts) == 1 {
+		for _, only := range t.hosts {
+			transport = only
+		}
+	}
+	if transport == nil {
+		transport = t.base
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport
+}
+
+func (t *ksmTransport) setRedirect(hostname, hostport string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.redirects[hostname] = hostport
+}
+
+func (t *ksmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	base := t.transportFor(req.URL.Host)
+	hostport, found := t.redirects[req.URL.Host]
+	t.mu.RUnlock()
+
+	if found {
+		req = req.Clone(req.Context())
+		req.URL.Host = hostport
+		req.Host = hostport
+	}
+	return base.RoundTrip(req)
+}
+
+// applyHostname sets the Keeper server from the provider `hostname` setting -
+// a region code, host name or https URL, optionally with a port. Empty hostname
+// keeps the hostname from the credential.
+func applyHostname(config core.IKeyValueStorage, hostname string) error {
+	hostname = strings.TrimSpace(hostname)
+	if hostname == "" {
+		return nil
+	}
+	if regionHostname, found := keeperRegions[strings.ToUpper(hostname)]; found {
+		config.Set(core.KEY_HOSTNAME, regionHostname)
+		return nil
+	}
+
+	serverUrl := hostname
+	if !strings.Contains(serverUrl, "://") {
+		serverUrl = "https://" + serverUrl
+	}
+	u, err := url.Parse(serverUrl)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid hostname '%s' - expected a host name or https URL", hostname)
+	}
+	if !strings.EqualFold(u.Scheme, "https") {
+		return fmt.Errorf("invalid hostname '%s' - only https is supported", hostname)
+	}
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return fmt.Errorf("invalid hostname '%s' - URL path is not supported", hostname)
+	}
+
+	config.Set(core.KEY_HOSTNAME, u.Hostname())
+	if port := u.Port(); port != "" && port != "443" {
+		defaultKsmTransport.setRedirect(u.Hostname(), u.Host)
+		installKsmTransport()
+	}
+	return nil
+}
@@ -0,0 +1,496 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func TestApplyHostname(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     string
+		wantErr  bool
+	}{
+		{"", "keepersecurity.com", false},
+		{"keepersecurity.eu", "keepersecurity.eu", false},
+		{"gov", "govcloud.keepersecurity.us", false},
+		{" https://keepersecurity.com.au/ ", "keepersecurity.com.au", false},
+		{"https://ksm.example.com:8443", "ksm.example.com", false},
+		{"http://ksm.example.com", "", true},
+		{"https://ksm.example.com/api", "", true},
+		{"https://:8443", "", true},
+	}
+	for _, tt := range tests {
+		config := core.NewMemoryKeyValueStorage()
+		config.Set(core.KEY_HOSTNAME, "keepersecurity.com")
+		err := applyHostname(config, tt.hostname)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("applyHostname(%q): error = %v, wantErr %v", tt.hostname, err, tt.wantErr)
+			continue
+		}
+		if got := config.Get(core.KEY_HOSTNAME); !tt.wantErr && got != tt.want {
+			t.Errorf("applyHostname(%q): hostname = %q, want %q", tt.hostname, got, tt.want)
+		}
+	}
+
+	defaultKsmTransport.mu.RLock()
+	redirect := defaultKsmTransport.redirects["ksm.example.com"]
+	defaultKsmTransport.mu.RUnlock()
+	if redirect != "ksm.example.com:8443" {
+		t.Errorf("expected requests for ksm.example.com redirected to port 8443, got %q", redirect)
+	}
+}
+
+func TestNetworkSettingsEnvDefaults(t *testing.T) {
+	t.Setenv("KEEPER_HOSTNAME", "EU")
+	t.Setenv("KEEPER_PROXY_URL", "http://proxy.example.com:3128")
+	t.Setenv("KEEPER_CA_CERT_FILE", "/etc/ssl/proxy-ca.pem")
+	t.Setenv("KEEPER_CA_CERT_PEM", "")
+	t.Setenv("KEEPER_INSECURE_SKIP_VERIFY", "true")
+
+	got := networkSettings{Hostname: "AU"}.withEnvDefaults()
+	want := networkSettings{
+		Hostname:           "AU",
+		ProxyUrl:           "http://proxy.example.com:3128",
+		CaCertFile:         "/etc/ssl/proxy-ca.pem",
+		InsecureSkipVerify: true,
+	}
+	if got != want {
+		t.Errorf("withEnvDefaults() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewBaseTransport(t *testing.T) {
+	if transport, err := newBaseTransport(networkSettings{Hostname: "EU"}); transport != nil || err != nil {
+		t.Errorf("expected no transport without proxy or TLS settings, got %v, %v", transport, err)
+	}
+
+	transport, err := newBaseTransport(networkSettings{ProxyUrl: "http://proxy.example.com:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("POST", "https://keepersecurity.com/api/rest/sm/v1/get_secret", nil)
+	if proxy, err := transport.Proxy(req); err != nil || proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		t.Errorf("unexpected proxy %v, %v", proxy, err)
+	}
+
+	s := httptest.NewTLSServer(http.NotFoundHandler())
+	defer s.Close()
+	caCertPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, []byte(caCertPem), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, settings := range []networkSettings{{CaCertPem: caCertPem}, {CaCertFile: caCertFile}, {InsecureSkipVerify: true}} {
+		transport, err := newBaseTransport(settings)
+		if err != nil {
+			t.Fatalf("newBaseTransport(%+v): %v", settings, err)
+		}
+		rs, err := (&http.Client{Transport: transport}).Get(s.URL)
+		if err != nil {
+			t.Errorf("request with %+v failed: %v", settings, err)
+			continue
+		}
+		rs.Body.Close()
+	}
+	if _, err := (&http.Client{Transport: http.DefaultTransport}).Get(s.URL); err == nil {
+		t.Error("expected the test certificate to be untrusted by default")
+	}
+
+	for _, settings := range []networkSettings{
+		{ProxyUrl: "ftp://proxy.example.com"},
+		{ProxyUrl: "proxy.example.com:3128"},
+		{CaCertPem: "not a certificate"},
+		{CaCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+	} {
+		if _, err := newBaseTransport(settings); err == nil {
+			t.Errorf("newBaseTransport(%+v): expected an error", settings)
+		}
+	}
+}
+
+// TestConfigureNetworkCaCert connects to the KSM test server trusting its
+// certificate only through ca_cert_pem.
+func TestConfigureNetworkCaCert(t *testing.T) {
+	s := newKsmTestServer(t)
+	uid := s.AddRecord(s.AddFolder("", "shared"), map[string]interface{}{"type": "login", "title": "web"})
+	previous := defaultKsmTransport.setBase(nil)
+	t.Cleanup(func() { defaultKsmTransport.setBase(previous) })
+	t.Cleanup(func() { defaultKsmTransport.setHostTransport(s.host(), nil) })
+
+	config := core.NewMemoryKeyValueStorage(s.Credential())
+	if err := configureNetwork(config, networkSettings{Hostname: s.Hostname()}); err != nil {
+		t.Fatal(err)
+	}
+	client := newSecretsManagerClient(config)
+	if _, err := client.GetSecrets([]string{uid}); err == nil {
+		t.Fatal("expected a TLS error without the test server CA")
+	}
+
+	caCertPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+	if err := configureNetwork(config, networkSettings{Hostname: s.Hostname(), CaCertPem: caCertPem}); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := client.GetSecrets([]string{uid}); err != nil || len(records) != 1 || records[0].Title() != "web" {
+		t.Fatalf("GetSecrets with ca_cert_pem: %v", err)
+	}
+}
+
+// TestConfigureNetworkAliases configures two providers for different Keeper
+// hosts - the TLS settings of one don't apply to the other or to other
+// requests, and configuring a host again without settings resets them.
+func TestConfigureNetworkAliases(t *testing.T) {
+	insecure := newKsmTestServer(t)
+	insecureUid := insecure.AddRecord(insecure.AddFolder("", "shared"), map[string]interface{}{"type": "login", "title": "web"})
+	verified := newKsmTestServer(t)
+	verifiedUid := verified.AddRecord(verified.AddFolder("", "shared"), map[string]interface{}{"type": "login", "title": "db"})
+	previous := defaultKsmTransport.setBase(nil)
+	t.Cleanup(func() { defaultKsmTransport.setBase(previous) })
+
+	// the same server address by another host name
+	insecureHostname := strings.Replace(insecure.URL, "127.0.0.1", "localhost", 1)
+	insecureConfig := core.NewMemoryKeyValueStorage(insecure.Credential())
+	if err := configureNetwork(insecureConfig, networkSettings{Hostname: insecureHostname, InsecureSkipVerify: true}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { defaultKsmTransport.setHostTransport("localhost", nil) })
+	verifiedConfig := core.NewMemoryKeyValueStorage(verified.Credential())
+	if err := configureNetwork(verifiedConfig, networkSettings{Hostname: verified.Hostname()}); err != nil {
+		t.Fatal(err)
+	}
+
+	insecureClient := newSecretsManagerClient(insecureConfig)
+	if _, err := insecureClient.GetSecrets([]string{insecureUid}); err != nil {
+		t.Fatalf("GetSecrets with insecure_skip_verify: %v", err)
+	}
+	if _, err := newSecretsManagerClient(verifiedConfig).GetSecrets([]string{verifiedUid}); err == nil {
+		t.Error("insecure_skip_verify of the other provider applied to verified requests")
+	}
+	if rs, err := http.DefaultClient.Get(verified.URL); err == nil {
+		rs.Body.Close()
+		t.Error("insecure_skip_verify applied to other http.DefaultClient requests")
+	}
+
+	if err := configureNetwork(insecureConfig, networkSettings{Hostname: insecureHostname}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insecureClient.GetSecrets([]string{insecureUid}); err == nil {
+		t.Error("insecure_skip_verify kept after configuring the host without it")
+	}
+}
+
+func TestKsmTransportFileHosts(t *testing.T) {
+	keeper := &http.Transport{}
+	transport := &ksmTransport{base: http.DefaultTransport, redirects: map[string]string{}, hosts: map[string]http.RoundTripper{}}
+	transport.setHostTransport("keepersecurity.com", keeper)
+	if got := transport.transportFor("keepersecurity.com"); got != keeper {
+		t.Error("Keeper host not routed through its transport")
+	}
+	// file transfers of the only configured provider
+	if got := transport.transportFor("storage.example.com"); got != keeper {
+		t.Error("file host not routed through the transport of the only Keeper host")
+	}
+	transport.setHostTransport("keepersecurity.eu", nil)
+	if got := transport.transportFor("storage.example.com"); got != http.DefaultTransport {
+		t.Error("file host routed through a provider transport with several Keeper hosts configured")
+	}
+	if got := transport.transportFor("keepersecurity.eu"); got != http.DefaultTransport {
+		t.Error("Keeper host without settings not routed through the base transport")
+	}
+}
+
+func newKsmTestServerClient(t *testing.T, s *ksmTestServer) ksmClient {
+	t.Helper()
+	config := core.NewMemoryKeyValueStorage(s.Credential())
+	if err := applyHostname(config, s.Hostname()); err != nil {
+		t.Fatal(err)
+	}
+	return newSecretsManagerClient(config)
+}
+
+func TestKsmTestServerClient(t *testing.T) {
+	ctx := context.Background()
+	s := newKsmTestServer(t)
+	delay := throttleRetryDelay
+	throttleRetryDelay = time.Millisecond
+	t.Cleanup(func() { throttleRetryDelay = delay })
+
+	folderUid := s.AddFolder("", "shared")
+	subfolderUid := s.AddFolder(folderUid, "sub")
+	uid := s.AddRecord(subfolderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+	s.AddFile(uid, "cert.pem", []byte("certificate"))
+	client := newKsmTestServerClient(t, s)
+
+	record, err := getRecord(ctx, uid, "", client)
+	if err != nil {
+		t.Fatalf("getRecord: %v", err)
+	}
+	if record.Title() != "web" || record.FolderUid() != folderUid || record.InnerFolderUid() != subfolderUid {
+		t.Errorf("unexpected record: title=%q folder=%q inner=%q", record.Title(), record.FolderUid(), record.InnerFolderUid())
+	}
+	if len(record.Files) != 1 || string(record.Files[0].GetFileData()) != "certificate" {
+		t.Errorf("unexpected files: %v", record.Files)
+	}
+	if values, err := getNotation(ctx, client, uid+"/field/login"); err != nil || len(values) != 1 || values[0] != "admin" {
+		t.Errorf("getNotation = %v, %v", values, err)
+	}
+
+	// update, retried after being throttled
+	record.SetTitle("renamed")
+	s.throttle = 1
+	if err := saveRecord(ctx, record, client); err != nil {
+		t.Fatalf("saveRecord: %v", err)
+	}
+	if s.calls["update_secret"] != 2 {
+		t.Errorf("update_secret called %d times, want 2", s.calls["update_secret"])
+	}
+	if got := s.Record(uid)["title"]; got != "renamed" {
+		t.Errorf("title = %v, want renamed", got)
+	}
+
+	// create in a subfolder, then delete
+	newUid, err := createRecord(ctx, "", subfolderUid, core.NewRecordCreate("login", "created"), client)
+	if err != nil {
+		t.Fatalf("createRecord: %v", err)
+	}
+	if created, err := getRecord(ctx, newUid, "", client); err != nil || created.Title() != "created" || created.InnerFolderUid() != subfolderUid {
+		t.Errorf("created record not found in subfolder: %v", err)
+	}
+	if err := deleteRecord(ctx, newUid, client); err != nil {
+		t.Fatalf("deleteRecord: %v", err)
+	}
+	if s.Record(newUid) != nil {
+		t.Error("record not deleted")
+	}
+
+	// folders
+	teamUid, err := createFolderWithOptions(ctx, client, &core.CreateOptions{FolderUid: folderUid, SubFolderUid: subfolderUid}, "team", nil)
+	if err != nil {
+		t.Fatalf("createFolder: %v", err)
+	}
+	if err := updateFolder(ctx, client, teamUid, "team-renamed", nil); err != nil {
+		t.Fatalf("updateFolder: %v", err)
+	}
+	if name, parent, _ := s.Folder(teamUid); name != "team-renamed" || parent != subfolderUid {
+		t.Errorf("unexpected folder: name=%q parent=%q", name, parent)
+	}
+	folders, err := getFolders(ctx, client)
+	if err != nil || len(folders) != 3 {
+		t.Fatalf("getFolders: %d folders, %v", len(folders), err)
+	}
+	for _, f := range folders {
+		if f.FolderUid == teamUid && f.Name != "team-renamed" {
+			t.Errorf("folder name decrypted as %q", f.Name)
+		}
+	}
+	if statuses, err := deleteFolders(ctx, client, []string{subfolderUid}, false); err != nil || statuses[subfolderUid] == "ok" {
+		t.Errorf("expected non-empty folder not deleted: %v, %v", statuses, err)
+	}
+	if statuses, err := deleteFolders(ctx, client, []string{subfolderUid}, true); err != nil || statuses[subfolderUid] != "ok" {
+		t.Fatalf("forced deleteFolder: %v, %v", statuses, err)
+	}
+	if _, _, found := s.Folder(teamUid); found || s.Record(uid) != nil {
+		t.Error("folder contents not deleted")
+	}
+}
+
+// testObjectValue returns an object of objType with the given attributes set and the rest null.
+func testObjectValue(objType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attrs := map[string]tftypes.Value{}
+	for name, attrType := range objType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attrs[name] = value
+		} else {
+			attrs[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tftypes.NewValue(objType, attrs)
+}
+
+func testDynamicValue(t *testing.T, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func testDiagnosticsError(diags []*tfprotov6.Diagnostic) error {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	return nil
+}
+
+func testStringAttribute(t *testing.T, dv *tfprotov6.DynamicValue, objType tftypes.Object, name string) string {
+	t.Helper()
+	value, err := dv.Unmarshal(objType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := value.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	if err := attrs[name].As(&s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// newTestProviderServer returns the muxed provider server configured for the
+// local KSM server and its schemas.
+func newTestProviderServer(t *testing.T, s *ksmTestServer) (tfprotov6.ProviderServer, *tfprotov6.GetProviderSchemaResponse) {
+	t.Helper()
+	return newTestProviderServerConfig(t, s, nil)
+}
+
+// newTestProviderServerConfig is newTestProviderServer with additional provider settings.
+func newTestProviderServerConfig(t *testing.T, s *ksmTestServer, config map[string]tftypes.Value) (tfprotov6.ProviderServer, *tfprotov6.GetProviderSchemaResponse) {
+	t.Helper()
+	ctx := context.Background()
+	factory, err := ProtoV6ProviderServerFactory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := factory()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]tftypes.Value{
+		"credential": tftypes.NewValue(tftypes.String, s.Credential()),
+		"hostname":   tftypes.NewValue(tftypes.String, s.Hostname()),
+	}
+	for name, value := range config {
+		values[name] = value
+	}
+	providerConfig := testObjectValue(schemas.Provider.ValueType().(tftypes.Object), values)
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.10.0",
+		Config:           testDynamicValue(t, providerConfig),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(configured.Diagnostics); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	return server, schemas
+}
+
+// TestProtoV6ProviderServerKsmTestServer runs both muxed providers against the
+// local KSM server - configure, data source read and ephemeral resource open.
+func TestProtoV6ProviderServerKsmTestServer(t *testing.T) {
+	ctx := context.Background()
+	s := newKsmTestServer(t)
+	clientFactory := newKsmClient
+	newKsmClient = newSecretsManagerClient
+	t.Cleanup(func() { newKsmClient = clientFactory })
+
+	folderUid := s.AddFolder("", "shared")
+	uid := s.AddRecord(folderUid, map[string]interface{}{
+		"type":   "login",
+		"title":  "web",
+		"fields": []interface{}{map[string]interface{}{"type": "login", "value": []interface{}{"admin"}}},
+	})
+
+	server, schemas := newTestProviderServer(t, s)
+
+	dataSourceType := schemas.DataSourceSchemas["secretsmanager_login"].ValueType().(tftypes.Object)
+	read, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "secretsmanager_login",
+		Config: testDynamicValue(t, testObjectValue(dataSourceType, map[string]tftypes.Value{
+			"path": tftypes.NewValue(tftypes.String, uid),
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(read.Diagnostics); err != nil {
+		t.Fatalf("read data source: %v", err)
+	}
+	if got := testStringAttribute(t, read.State, dataSourceType, "login"); got != "admin" {
+		t.Errorf("data source login = %q, want admin", got)
+	}
+
+	ephemeralType := schemas.EphemeralResourceSchemas["secretsmanager_login"].ValueType().(tftypes.Object)
+	opened, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "secretsmanager_login",
+		Config: testDynamicValue(t, testObjectValue(ephemeralType, map[string]tftypes.Value{
+			"path": tftypes.NewValue(tftypes.String, uid),
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(opened.Diagnostics); err != nil {
+		t.Fatalf("open ephemeral resource: %v", err)
+	}
+	if got := testStringAttribute(t, opened.Result, ephemeralType, "title"); got != "web" {
+		t.Errorf("ephemeral title = %q, want web", got)
+	}
+}
+
+func TestAccKsmTestServerLogin(t *testing.T) {
+	s := newKsmTestServer(t)
+	clientFactory := newKsmClient
+	newKsmClient = newSecretsManagerClient
+	t.Cleanup(func() { newKsmClient = clientFactory })
+
+	folderUid := s.AddFolder("", "shared")
+	config := fmt.Sprintf(`
+		provider "secretsmanager" {
+			credential = "%v"
+			hostname   = "%v"
+		}
+		resource "secretsmanager_login" "login" {
+			folder_uid = "%v"
+			title      = "tf_acc_test_ksm_server"
+			login {
+				value = "admin"
+			}
+		}
+	`, s.Credential(), s.Hostname(), folderUid)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("secretsmanager_login.login", "title", "tf_acc_test_ksm_server"),
+					resource.TestCheckResourceAttr("secretsmanager_login.login", "login.0.value", "admin"),
+					resource.TestCheckResourceAttrWith("secretsmanager_login.login", "uid", func(uid string) error {
+						if s.Record(uid) == nil {
+							return fmt.Errorf("record %s not found on the KSM test server", uid)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,154 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// unmanagedFieldsProviderServer warns in the plan of a record update about the
+// custom fields the apply removes because configuration doesn't declare them.
+// SDKv2 CustomizeDiff can't return warnings, so the plan of the SDKv2 server is
+// checked in front of it.
+type unmanagedFieldsProviderServer struct {
+	tfprotov6.ProviderServer
+	meta func() interface{}
+
+	once  sync.Once
+	types map[string]tftypes.Type
+}
+
+func newUnmanagedFieldsProviderServer(server tfprotov6.ProviderServer, meta func() interface{}) *unmanagedFieldsProviderServer {
+	return &unmanagedFieldsProviderServer{ProviderServer: server, meta: meta}
+}
+
+// resourceType returns the value type of the resource - nil when unknown.
+func (s *unmanagedFieldsProviderServer) resourceType(ctx context.Context, typeName string) tftypes.Type {
+	s.once.Do(func() {
+		s.types = map[string]tftypes.Type{}
+		resp, err := s.ProviderServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+		if err != nil || resp == nil {
+			return
+		}
+		for name, resourceSchema := range resp.ResourceSchemas {
+			s.types[name] = resourceSchema.ValueType()
+		}
+	})
+	return s.types[typeName]
+}
+
+func (s *unmanagedFieldsProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	provider, ok := s.meta().(providerMeta)
+	if !ok || provider.unmanagedFields == UnmanagedFieldsPreserve {
+		return resp, nil
+	}
+	typ := s.resourceType(ctx, req.TypeName)
+	if typ == nil {
+		return resp, nil
+	}
+	state, ok := dynamicValueAttributes(req.PriorState, typ)
+	if !ok {
+		return resp, nil
+	}
+	config, ok := dynamicValueAttributes(req.Config, typ)
+	if !ok {
+		return resp, nil
+	}
+	declared, ok := customItemsOf(config["custom"])
+	if !ok {
+		return resp, nil
+	}
+	existing, _ := customItemsOf(state["custom"])
+	ignored, _ := stringsOf(config["ignore_custom_labels"])
+
+	if undeclared := undeclaredCustomLabels(existing, declared, ignored, provider.unmanagedFields); len(undeclared) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov6.Diagnostic{
+			Severity: tfprotov6.DiagnosticSeverityWarning,
+			Summary:  "Record has custom fields not managed by Terraform",
+			Detail: fmt.Sprintf("%s has custom fields not declared in configuration: %q. "+
+				"This apply removes them unless they are declared, listed in ignore_custom_labels, "+
+				"or the provider is configured with unmanaged_fields = \"preserve\".", req.TypeName, undeclared),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("custom"),
+		})
+	}
+	return resp, nil
+}
+
+// dynamicValueAttributes decodes the attributes of a known, non-null object.
+func dynamicValueAttributes(value *tfprotov6.DynamicValue, typ tftypes.Type) (map[string]tftypes.Value, bool) {
+	if value == nil {
+		return nil, false
+	}
+	v, err := value.Unmarshal(typ)
+	if err != nil || !v.IsKnown() || v.IsNull() {
+		return nil, false
+	}
+	attributes := map[string]tftypes.Value{}
+	if err := v.As(&attributes); err != nil {
+		return nil, false
+	}
+	return attributes, true
+}
+
+// customItemsOf returns the type and label of every custom field block - false
+// when the list isn't known yet.
+func customItemsOf(value tftypes.Value) ([]interface{}, bool) {
+	items := []interface{}{}
+	if value.Type() == nil || value.IsNull() {
+		return items, true
+	}
+	if !value.IsKnown() {
+		return nil, false
+	}
+	var blocks []tftypes.Value
+	if err := value.As(&blocks); err != nil {
+		return nil, false
+	}
+	for _, block := range blocks {
+		attributes := map[string]tftypes.Value{}
+		if !block.IsKnown() || block.As(&attributes) != nil {
+			return nil, false
+		}
+		item := map[string]interface{}{}
+		for _, name := range []string{"type", "label"} {
+			var s string
+			if !attributes[name].IsKnown() {
+				return nil, false
+			}
+			if !attributes[name].IsNull() {
+				if err := attributes[name].As(&s); err != nil {
+					return nil, false
+				}
+			}
+			item[name] = s
+		}
+		items = append(items, item)
+	}
+	return items, true
+}
+
+// stringsOf returns the known elements of a list of strings.
+func stringsOf(value tftypes.Value) ([]string, bool) {
+	if value.Type() == nil || value.IsNull() || !value.IsKnown() {
+		return nil, false
+	}
+	var elements []tftypes.Value
+	if err := value.As(&elements); err != nil {
+		return nil, false
+	}
+	strs := []string{}
+	for _, element := range elements {
+		var s string
+		if element.IsKnown() && !element.IsNull() && element.As(&s) == nil {
+			strs = append(strs, s)
+		}
+	}
+	return strs, true
+}
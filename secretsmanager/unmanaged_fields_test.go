@@ -0,0 +1,279 @@
+package secretsmanager
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func customItem(fieldType, label, value string) map[string]interface{} {
+	return map[string]interface{}{"type": fieldType, "label": label, "value": []interface{}{value}}
+}
+
+func customLabels(items []interface{}) []string {
+	labels := []string{}
+	for _, item := range items {
+		labels = append(labels, item.(map[string]interface{})["label"].(string))
+	}
+	return labels
+}
+
+func TestManagedCustomItems(t *testing.T) {
+	vault := []interface{}{
+		customItem("text", "Added In UI", "x"),
+		customItem("secret", "API Key", "k"),
+		customItem("text", "Owner", "o"),
+		customItem("text", "Rotation Notes", "r"),
+	}
+	declared := []interface{}{
+		map[string]interface{}{"type": "text", "label": "Owner"},
+		map[string]interface{}{"type": "secret", "label": "API Key"},
+	}
+	ignored := []string{"rotation notes"}
+
+	managed, undeclared := managedCustomItems(vault, declared, ignored, UnmanagedFieldsPreserve)
+	if got := customLabels(managed); !reflect.DeepEqual(got, []string{"Owner", "API Key"}) {
+		t.Errorf("preserve: managed = %v, want declared fields in declaration order", got)
+	}
+	if !reflect.DeepEqual(undeclared, []string{"Added In UI"}) {
+		t.Errorf("preserve: undeclared = %v", undeclared)
+	}
+
+	managed, undeclared = managedCustomItems(vault, declared, ignored, UnmanagedFieldsRemove)
+	if got := customLabels(managed); !reflect.DeepEqual(got, []string{"Added In UI", "API Key", "Owner"}) {
+		t.Errorf("remove: managed = %v, want all non-ignored fields in record order", got)
+	}
+	if !reflect.DeepEqual(undeclared, []string{"Added In UI"}) {
+		t.Errorf("remove: undeclared = %v", undeclared)
+	}
+
+	// a declared type mismatch does not claim the field
+	declared = []interface{}{map[string]interface{}{"type": "secret", "label": "Owner"}}
+	managed, _ = managedCustomItems(vault, declared, nil, UnmanagedFieldsPreserve)
+	if len(managed) != 0 {
+		t.Errorf("expected no managed fields on type mismatch, got %v", managed)
+	}
+}
+
+func TestMergeCustomFields(t *testing.T) {
+	current := []interface{}{
+		customItem("text", "Added In UI", "x"),
+		customItem("secret", "API Key", "old"),
+		customItem("text", "Dropped", "d"),
+		customItem("text", "Private Key Passphrase", "p"),
+	}
+	declared := []interface{}{
+		map[string]interface{}{"type": "secret", "label": "API Key"},
+		map[string]interface{}{"type": "text", "label": "Dropped"},
+	}
+	fields := []interface{}{
+		customItem("secret", "API Key", "new"),
+		customItem("text", "Owner", "o"),
+	}
+	ignored := []string{"Private Key Passphrase"}
+
+	merged := mergeCustomFields(current, declared, fields, ignored, UnmanagedFieldsPreserve)
+	if got := customLabels(merged); !reflect.DeepEqual(got, []string{"Added In UI", "API Key", "Private Key Passphrase", "Owner"}) {
+		t.Errorf("preserve: merged = %v", got)
+	}
+	if v := merged[1].(map[string]interface{})["value"].([]interface{})[0]; v != "new" {
+		t.Errorf("preserve: API Key = %v, want new", v)
+	}
+
+	merged = mergeCustomFields(current, declared, fields, ignored, UnmanagedFieldsRemove)
+	if got := customLabels(merged); !reflect.DeepEqual(got, []string{"Private Key Passphrase", "API Key", "Owner"}) {
+		t.Errorf("remove: merged = %v", got)
+	}
+}
+
+func TestSetCustomResourceData(t *testing.T) {
+	record := &core.Record{
+		Uid: "record-uid",
+		RecordDict: map[string]interface{}{
+			"type": "login",
+			"custom": []interface{}{
+				customItem("text", "Owner", "o"),
+				customItem("text", "Added In UI", "x"),
+				customItem("text", "Ignored", "i"),
+			},
+		},
+	}
+	raw := map[string]interface{}{
+		"custom": []interface{}{
+			map[string]interface{}{"type": "text", "label": "Owner", "value": "o"},
+		},
+		"ignore_custom_labels": []interface{}{"Ignored"},
+	}
+
+	// undeclared fields are warned about in the plan, not on every refresh
+	d := schema.TestResourceDataRaw(t, resourceLogin().Schema, raw)
+	if diags := setCustomResourceData(context.Background(), d, record, UnmanagedFieldsRemove); len(diags) != 0 {
+		t.Errorf("remove: expected no diagnostics, got %v", diags)
+	}
+	if got := len(d.Get("custom").([]interface{})); got != 2 {
+		t.Errorf("remove: expected 2 custom fields in state, got %d", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceLogin().Schema, raw)
+	if diags := setCustomResourceData(context.Background(), d, record, UnmanagedFieldsPreserve); len(diags) != 0 {
+		t.Errorf("preserve: expected no diagnostics, got %v", diags)
+	}
+	if got := len(d.Get("custom").([]interface{})); got != 1 {
+		t.Errorf("preserve: expected 1 custom field in state, got %d", got)
+	}
+}
+
+func TestUndeclaredCustomLabels(t *testing.T) {
+	state := []interface{}{
+		customItem("text", "Owner", "o"),
+		customItem("text", "Added In UI", "x"),
+		customItem("secret", "API Key", "k"),
+	}
+	declared := []interface{}{
+		map[string]interface{}{"type": "Text", "label": "Owner"},
+	}
+	if got := undeclaredCustomLabels(state, declared, []string{"api key"}, UnmanagedFieldsRemove); !reflect.DeepEqual(got, []string{"Added In UI"}) {
+		t.Errorf("remove: undeclared = %v", got)
+	}
+	if got := undeclaredCustomLabels(state, declared, nil, UnmanagedFieldsPreserve); len(got) != 0 {
+		t.Errorf("preserve: undeclared = %v, want none", got)
+	}
+}
+
+// TestUnmanagedFieldsPlanWarning plans a record update through the mux server
+// and expects the warning about the undeclared custom field the apply removes.
+func TestUnmanagedFieldsPlanWarning(t *testing.T) {
+	ctx := context.Background()
+	for mode, wantWarning := range map[string]bool{UnmanagedFieldsRemove: true, UnmanagedFieldsPreserve: false} {
+		t.Run(mode, func(t *testing.T) {
+			server, schemas := newTestProviderServerConfig(t, newKsmTestServer(t), map[string]tftypes.Value{
+				"unmanaged_fields": tftypes.NewValue(tftypes.String, mode),
+			})
+			objType := schemas.ResourceSchemas["secretsmanager_login"].ValueType().(tftypes.Object)
+			customType := objType.AttributeTypes["custom"].(tftypes.List)
+			custom := func(labels ...string) tftypes.Value {
+				blocks := []tftypes.Value{}
+				for _, label := range labels {
+					blocks = append(blocks, testObjectValue(customType.ElementType.(tftypes.Object), map[string]tftypes.Value{
+						"type":  tftypes.NewValue(tftypes.String, "text"),
+						"label": tftypes.NewValue(tftypes.String, label),
+						"value": tftypes.NewValue(tftypes.String, "v"),
+					}))
+				}
+				return tftypes.NewValue(customType, blocks)
+			}
+			prior := testObjectValue(objType, map[string]tftypes.Value{
+				"id":     tftypes.NewValue(tftypes.String, "uid"),
+				"uid":    tftypes.NewValue(tftypes.String, "uid"),
+				"title":  tftypes.NewValue(tftypes.String, "web"),
+				"custom": custom("Owner", "Added In UI"),
+			})
+			config := testObjectValue(objType, map[string]tftypes.Value{
+				"title":  tftypes.NewValue(tftypes.String, "web"),
+				"custom": custom("Owner"),
+			})
+
+			resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "secretsmanager_login",
+				PriorState:       testDynamicValue(t, prior),
+				ProposedNewState: testDynamicValue(t, config),
+				Config:           testDynamicValue(t, config),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := testDiagnosticsError(resp.Diagnostics); err != nil {
+				t.Fatalf("plan: %v", err)
+			}
+			warned := false
+			for _, d := range resp.Diagnostics {
+				if d.Severity == tfprotov6.DiagnosticSeverityWarning && strings.Contains(d.Detail, `"Added In UI"`) {
+					warned = true
+				}
+			}
+			if warned != wantWarning {
+				t.Errorf("warned = %v, want %v: %v", warned, wantWarning, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// TestSetCustomResourceDataImportPreserve verifies an import in preserve mode
+// takes ownership of the record's custom fields - there is no prior state - and
+// the next apply keeps the fields still declared in place.
+func TestSetCustomResourceDataImportPreserve(t *testing.T) {
+	record := &core.Record{
+		Uid: "record-uid",
+		RecordDict: map[string]interface{}{
+			"type": "login",
+			"custom": []interface{}{
+				customItem("text", "Owner", "o"),
+				customItem("text", "Added In UI", "x"),
+			},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{})
+	diags := setCustomResourceData(withRecordImport(context.Background()), d, record, UnmanagedFieldsPreserve)
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+	imported := d.Get("custom").([]interface{})
+	if got := customLabels(imported); !reflect.DeepEqual(got, []string{"Owner", "Added In UI"}) {
+		t.Fatalf("imported custom = %v, want all record fields", got)
+	}
+
+	// the refresh after import keeps the imported fields
+	d = schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{"custom": imported})
+	setCustomResourceData(context.Background(), d, record, UnmanagedFieldsPreserve)
+	if got := customLabels(d.Get("custom").([]interface{})); !reflect.DeepEqual(got, []string{"Owner", "Added In UI"}) {
+		t.Errorf("refreshed custom = %v, want all record fields", got)
+	}
+
+	// without the import marker nothing undeclared is claimed
+	d = schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{})
+	setCustomResourceData(context.Background(), d, record, UnmanagedFieldsPreserve)
+	if got := len(d.Get("custom").([]interface{})); got != 0 {
+		t.Errorf("refresh without prior fields: expected no custom fields, got %d", got)
+	}
+}
+
+// TestProviderSchemasMatch verifies unmanaged_fields is mirrored in both muxed
+// providers - the mux server rejects differing provider schemas.
+func TestProviderSchemasMatch(t *testing.T) {
+	factory, err := ProtoV6ProviderServerFactory(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := factory().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("provider schema error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+func TestFwProviderUnmanagedFieldsValidation(t *testing.T) {
+	ctx := context.Background()
+	for value, valid := range map[string]bool{UnmanagedFieldsRemove: true, UnmanagedFieldsPreserve: true, "keep": false} {
+		resp := &validator.StringResponse{}
+		stringOneOf(UnmanagedFieldsRemove, UnmanagedFieldsPreserve).ValidateString(ctx, validator.StringRequest{
+			Path:        path.Root("unmanaged_fields"),
+			ConfigValue: types.StringValue(value),
+		}, resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%q: got errors %v, want valid = %v", value, resp.Diagnostics, valid)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+// Provider write policy settings - both providers read them with the same
+// environment variable fallbacks.
+const (
+	readOnlyDescription = "Reject every change made by managed resources - creates, updates and deletes fail at plan time. " +
+		"Data sources and ephemeral resources are not affected. Can also be sourced from the `KEEPER_READ_ONLY` environment variable."
+	allowedWriteFoldersDescription = "UIDs of the folders where managed resources may create, update and delete records and folders - " +
+		"subfolders included. Empty allows every folder shared to the application. " +
+		"Can also be sourced from the `KEEPER_ALLOWED_WRITE_FOLDERS` environment variable (comma separated)."
+)
+
+var errReadOnly = errors.New("the provider is configured with read_only = true - changes to Keeper records and folders are not allowed")
+
+// writePolicy limits the changes managed resources make through the provider
+// so a misconfigured module can't write outside the folders it owns.
+type writePolicy struct {
+	ReadOnly       bool
+	AllowedFolders []string
+}
+
+// withEnvDefaults fills unset values from the environment.
+func (w writePolicy) withEnvDefaults() writePolicy {
+	if !w.ReadOnly {
+		w.ReadOnly, _ = core.StrToBool(envDefault("KEEPER_READ_ONLY"))
+	}
+	if len(w.AllowedFolders) == 0 {
+		for _, uid := range strings.Split(envDefault("KEEPER_ALLOWED_WRITE_FOLDERS"), ",") {
+			if uid = strings.TrimSpace(uid); uid != "" {
+				w.AllowedFolders = append(w.AllowedFolders, uid)
+			}
+		}
+	}
+	return w
+}
+
+func (w writePolicy) isEmpty() bool {
+	return !w.ReadOnly && len(w.AllowedFolders) == 0
+}
+
+// guardedClient carries the write policy of the provider configuration with
+// its client - the write helpers look it up with writePolicyOf.
+type guardedClient struct {
+	ksmClient
+	policy writePolicy
+}
+
+func (c *guardedClient) unwrap() ksmClient { return c.ksmClient }
+
+// wrappedClient is implemented by the clients decorating another ksmClient.
+type wrappedClient interface {
+	unwrap() ksmClient
+}
+
+// configureWritePolicy attaches the policy to the client. The client is
+// returned unchanged when the policy allows everything.
+func configureWritePolicy(client ksmClient, policy writePolicy) ksmClient {
+	if policy.isEmpty() {
+		return client
+	}
+	for i, uid := range policy.AllowedFolders {
+		policy.AllowedFolders[i] = strings.TrimSpace(uid)
+	}
+	return &guardedClient{ksmClient: client, policy: policy}
+}
+
+// writePolicyOf returns the write policy of the client, nil if it has none.
+func writePolicyOf(client ksmClient) *writePolicy {
+	for client != nil {
+		if c, ok := client.(*guardedClient); ok {
+			return &c.policy
+		}
+		w, ok := client.(wrappedClient)
+		if !ok {
+			return nil
+		}
+		client = w.unwrap()
+	}
+	return nil
+}
+
+// allowsFolder checks the folder and its parents against allowed_write_folders.
+func (w *writePolicy) allowsFolder(folderUid string, folders []*core.KeeperFolder) bool {
+	if len(w.AllowedFolders) == 0 {
+		return true
+	}
+	for uid, depth := folderUid, 0; uid != "" && depth <= len(folders); depth++ {
+		if slices.Contains(w.AllowedFolders, uid) {
+			return true
+		}
+		parentUid := ""
+		for _, f := range folders {
+			if f.FolderUid == uid {
+				parentUid = f.ParentUid
+				break
+			}
+		}
+		uid = parentUid
+	}
+	return false
+}
+
+// checkFolder returns an error if changes in the folder are not allowed. The
+// folders shared to the application are fetched when needed and not given.
+func (w *writePolicy) checkFolder(ctx context.Context, folderUid string, folders []*core.KeeperFolder, client ksmClient) error {
+	if w == nil {
+		return nil
+	}
+	if w.ReadOnly {
+		return errReadOnly
+	}
+	if w.allowsFolder(folderUid, nil) {
+		return nil
+	}
+	if len(folders) == 0 {
+		var err error
+		if folders, err = getFolders(ctx, client); err != nil {
+			return err
+		}
+	}
+	if !w.allowsFolder(folderUid, folders) {
+		return fmt.Errorf("folder '%s' is not in allowed_write_folders of the provider configuration", folderUid)
+	}
+	return nil
+}
+
+// checkRecord returns an error if changes to the record are not allowed.
+func (w *writePolicy) checkRecord(ctx context.Context, record *core.Record, client ksmClient) error {
+	if w == nil {
+		return nil
+	}
+	folderUid := record.InnerFolderUid()
+	if folderUid == "" {
+		folderUid = record.FolderUid()
+	}
+	if err := w.checkFolder(ctx, folderUid, nil, client); err != nil {
+		return fmt.Errorf("record UID %s: %w", record.Uid, err)
+	}
+	return nil
+}
+
+// checkRecordUid is checkRecord for a record known only by its UID.
+func (w *writePolicy) checkRecordUid(ctx context.Context, recordUid string, client ksmClient) error {
+	if w == nil {
+		return nil
+	}
+	if w.ReadOnly {
+		return errReadOnly
+	}
+	if len(w.AllowedFolders) == 0 {
+		return nil
+	}
+	records, err := getSecrets(ctx, client, []string{recordUid})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		// nothing the application can change
+		return nil
+	}
+	return w.checkRecord(ctx, records[0], client)
+}
+
+// readOnlyCustomizeDiff fails the plan of resources created or updated while
+// the provider is read only - deletes are failed by readOnlyProviderServer.
+func readOnlyCustomizeDiff(next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if provider, ok := m.(providerMeta); ok {
+			if policy := writePolicyOf(provider.client); policy != nil && policy.ReadOnly {
+				if d.Id() == "" {
+					return errReadOnly
+				}
+				// deletion_protection and on_destroy don't change the record
+				for _, key := range d.GetChangedKeysPrefix("") {
+					if !slices.Contains(recordLifecycleAttributes, key) {
+						return errReadOnly
+					}
+				}
+			}
+		}
+		if next != nil {
+			return next(ctx, d, m)
+		}
+		return nil
+	}
+}
+
+// readOnlyProviderServer fails the plan of resources deleted while the provider
+// is read only. SDKv2 plans deletes without calling CustomizeDiff, so the planned
+// null state is checked in front of the SDKv2 server. Terraform plans deletes with
+// the provider only when the server enables the PlanDestroy capability.
+type readOnlyProviderServer struct {
+	tfprotov6.ProviderServer
+	meta func() interface{}
+}
+
+func (s readOnlyProviderServer) GetMetadata(ctx context.Context, req *tfprotov6.GetMetadataRequest) (*tfprotov6.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withPlanDestroy(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func (s readOnlyProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withPlanDestroy(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func withPlanDestroy(capabilities *tfprotov6.ServerCapabilities) *tfprotov6.ServerCapabilities {
+	if capabilities == nil {
+		capabilities = &tfprotov6.ServerCapabilities{}
+	}
+	capabilities.PlanDestroy = true
+	return capabilities
+}
+
+func (s readOnlyProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	if req.PriorState != nil && req.ProposedNewState != nil {
+		priorNull, _ := req.PriorState.IsNull()
+		destroy, _ := req.ProposedNewState.IsNull()
+		if provider, ok := s.meta().(providerMeta); ok && destroy && !priorNull {
+			if policy := writePolicyOf(provider.client); policy != nil && policy.ReadOnly {
+				return &tfprotov6.PlanResourceChangeResponse{
+					Diagnostics: []*tfprotov6.Diagnostic{{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Read-only provider",
+						Detail:   fmt.Sprintf("%s cannot be deleted: %s", req.TypeName, errReadOnly),
+					}},
+				}, nil
+			}
+		}
+	}
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
@@ -0,0 +1,184 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/keeper-security/secrets-manager-go/core"
+)
+
+func TestWritePolicyEnvDefaults(t *testing.T) {
+	t.Setenv("KEEPER_READ_ONLY", "true")
+	t.Setenv("KEEPER_ALLOWED_WRITE_FOLDERS", " uid1, ,uid2")
+	policy := writePolicy{}.withEnvDefaults()
+	if !policy.ReadOnly || len(policy.AllowedFolders) != 2 || policy.AllowedFolders[1] != "uid2" {
+		t.Errorf("unexpected env defaults: %+v", policy)
+	}
+
+	vault := newFakeVault()
+	if client := configureWritePolicy(vault, writePolicy{}); client != vault || writePolicyOf(client) != nil {
+		t.Error("expected the client unchanged with an empty policy")
+	}
+}
+
+func TestWritePolicyAllowedFolders(t *testing.T) {
+	ctx := context.Background()
+	vault, allowedUid := newTestFakeVault(t)
+	subfolderUid := vault.AddFolder(allowedUid, "sub")
+	otherUid := vault.AddFolder("", "production")
+	allowedRecord := vault.AddRecord(subfolderUid, map[string]interface{}{"type": "login", "title": "dev"})
+	otherRecord := vault.AddRecord(otherUid, map[string]interface{}{"type": "login", "title": "prod"})
+
+	// the policy is found through the cache wrapper too
+	client, _ := newTestCachingClient(t, configureWritePolicy(vault, writePolicy{AllowedFolders: []string{allowedUid}}), false)
+
+	if _, err := createRecord(ctx, core.GenerateUid(), subfolderUid, core.NewRecordCreate("login", "new"), client); err != nil {
+		t.Errorf("create in an allowed subfolder: %v", err)
+	}
+	if _, err := createRecord(ctx, core.GenerateUid(), otherUid, core.NewRecordCreate("login", "new"), client); err == nil {
+		t.Error("expected an error creating a record outside allowed_write_folders")
+	}
+	if _, err := createFolder(ctx, otherUid, "team", client); err == nil {
+		t.Error("expected an error creating a folder outside allowed_write_folders")
+	}
+	if err := deleteFolder(ctx, otherUid, true, client); err == nil {
+		t.Error("expected an error deleting a folder outside allowed_write_folders")
+	}
+
+	for uid, allowed := range map[string]bool{allowedRecord: true, otherRecord: false} {
+		record, err := getRecord(ctx, uid, "", client)
+		if err != nil {
+			t.Fatalf("getRecord: %v", err)
+		}
+		record.SetTitle("renamed")
+		if err := saveRecord(ctx, record, client); (err == nil) != allowed {
+			t.Errorf("save %s: allowed=%v err=%v", record.Title(), allowed, err)
+		}
+		if err := deleteRecord(ctx, uid, client); (err == nil) != allowed {
+			t.Errorf("delete %s: allowed=%v err=%v", uid, allowed, err)
+		}
+	}
+	if vault.Record(otherRecord) == nil {
+		t.Error("record outside allowed_write_folders was deleted")
+	}
+}
+
+func TestWritePolicyReadOnly(t *testing.T) {
+	ctx := context.Background()
+	vault, folderUid := newTestFakeVault(t)
+	uid := vault.AddRecord(folderUid, map[string]interface{}{"type": "login", "title": "web"})
+	meta := providerMeta{client: configureWritePolicy(vault, writePolicy{ReadOnly: true})}
+
+	record, err := getRecord(ctx, uid, "", meta.client)
+	if err != nil {
+		t.Fatalf("getRecord: %v", err)
+	}
+	if err := saveRecord(ctx, record, meta.client); !errors.Is(err, errReadOnly) {
+		t.Errorf("save: expected errReadOnly, got %v", err)
+	}
+	if err := deleteRecord(ctx, uid, meta.client); !errors.Is(err, errReadOnly) {
+		t.Errorf("delete: expected errReadOnly, got %v", err)
+	}
+
+	r := Provider().ResourcesMap["secretsmanager_login"]
+	config := map[string]interface{}{"uid": uid, "folder_uid": folderUid, "title": "web"}
+	if _, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), meta); !errors.Is(err, errReadOnly) {
+		t.Errorf("plan create: expected errReadOnly, got %v", err)
+	}
+
+	// an unchanged resource still plans
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	d.SetId(uid)
+	if diags := resourceLoginRead(ctx, d, providerMeta{client: vault}); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta); err != nil {
+		t.Errorf("plan without changes: %v", err)
+	}
+	config["title"] = "renamed"
+	if _, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta); !errors.Is(err, errReadOnly) {
+		t.Errorf("plan update: expected errReadOnly, got %v", err)
+	}
+}
+
+// planStubServer records the plans passed through readOnlyProviderServer.
+type planStubServer struct {
+	tfprotov6.ProviderServer
+	plans int
+}
+
+func (s *planStubServer) PlanResourceChange(_ context.Context, _ *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	s.plans++
+	return &tfprotov6.PlanResourceChangeResponse{}, nil
+}
+
+func TestReadOnlyProviderServerDestroy(t *testing.T) {
+	ctx := context.Background()
+	vault, _ := newTestFakeVault(t)
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}}
+	dynamicValue := func(value interface{}) *tfprotov6.DynamicValue {
+		v, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, value))
+		if err != nil {
+			t.Fatalf("dynamic value: %v", err)
+		}
+		return &v
+	}
+	state := dynamicValue(map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "uid")})
+	destroy := &tfprotov6.PlanResourceChangeRequest{TypeName: "secretsmanager_login", PriorState: state, ProposedNewState: dynamicValue(nil)}
+	update := &tfprotov6.PlanResourceChangeRequest{TypeName: "secretsmanager_login", PriorState: state, ProposedNewState: state}
+
+	stub := &planStubServer{}
+	meta := providerMeta{client: configureWritePolicy(vault, writePolicy{ReadOnly: true})}
+	s := readOnlyProviderServer{ProviderServer: stub, meta: func() interface{} { return meta }}
+	resp, err := s.PlanResourceChange(ctx, destroy)
+	if err != nil || len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != tfprotov6.DiagnosticSeverityError {
+		t.Errorf("read only destroy: got %v, %v, want an error diagnostic", resp, err)
+	}
+	if stub.plans != 0 {
+		t.Error("read only destroy: plan passed to the SDKv2 server")
+	}
+	if _, err = s.PlanResourceChange(ctx, update); err != nil || stub.plans != 1 {
+		t.Errorf("read only update: got %v, want the plan passed to the SDKv2 server", err)
+	}
+
+	// writable and not yet configured providers plan deletes
+	for _, m := range []interface{}{providerMeta{client: vault}, nil} {
+		s = readOnlyProviderServer{ProviderServer: stub, meta: func() interface{} { return m }}
+		if resp, err = s.PlanResourceChange(ctx, destroy); err != nil || len(resp.Diagnostics) != 0 {
+			t.Errorf("destroy with %v: got %v, %v", m, resp, err)
+		}
+	}
+	if stub.plans != 3 {
+		t.Errorf("got %d plans passed to the SDKv2 server, want 3", stub.plans)
+	}
+}
+
+// TestReadOnlyProviderServerPlanDestroy plans a delete through the mux server of
+// a provider configured with read_only = true.
+func TestReadOnlyProviderServerPlanDestroy(t *testing.T) {
+	ctx := context.Background()
+	server, schemas := newTestProviderServerConfig(t, newKsmTestServer(t), map[string]tftypes.Value{
+		"read_only": tftypes.NewValue(tftypes.Bool, true),
+	})
+	objType := schemas.ResourceSchemas["secretsmanager_login"].ValueType().(tftypes.Object)
+	prior := testObjectValue(objType, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "uid")})
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "secretsmanager_login",
+		PriorState:       testDynamicValue(t, prior),
+		ProposedNewState: testDynamicValue(t, tftypes.NewValue(objType, nil)),
+		Config:           testDynamicValue(t, tftypes.NewValue(objType, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testDiagnosticsError(resp.Diagnostics); err == nil || !strings.Contains(err.Error(), "read_only") {
+		t.Errorf("plan destroy: got error %v, want the read_only error", err)
+	}
+}